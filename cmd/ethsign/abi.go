@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/juztin/ethsign/parser"
+	"github.com/juztin/ethsign/tx"
+)
+
+// abiEntry is a function, constructor, event or error of the ABI
+type abiEntry struct {
+	Type            string     `json:"type"`
+	Name            string     `json:"name,omitempty"`
+	Signature       string     `json:"signature"`
+	Selector        string     `json:"selector,omitempty"`
+	Topic           string     `json:"topic,omitempty"`
+	StateMutability string     `json:"stateMutability,omitempty"`
+	Inputs          []abiParam `json:"inputs"`
+	Outputs         []abiParam `json:"outputs,omitempty"`
+}
+
+// abiParam is a typed input, or output, with the components of tuples
+type abiParam struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Indexed    bool       `json:"indexed,omitempty"`
+	Components []abiParam `json:"components,omitempty"`
+}
+
+func validateABIArgs() error {
+	if len(args) > 1 {
+		return errors.New("Too many arguments, expected an optional function name, signature or selector")
+	} else if contractABI == nil && (len(args) == 0 || !strings.Contains(args[0], "(")) {
+		return errors.New("Must specify an ABI [--abi, --artifact], or a function signature")
+	}
+	return nil
+}
+
+// abiParams returns the arguments, and their tuple components
+func abiParams(args abi.Arguments) []abiParam {
+	params := make([]abiParam, len(args))
+	for i, a := range args {
+		params[i] = abiParam{Name: a.Name, Type: a.Type.String(), Indexed: a.Indexed, Components: abiComponents(a.Type)}
+	}
+	return params
+}
+
+// abiComponents returns the components of tuples, and arrays of tuples
+func abiComponents(t abi.Type) []abiParam {
+	for t.T == abi.SliceTy || t.T == abi.ArrayTy {
+		t = *t.Elem
+	}
+	if t.T != abi.TupleTy {
+		return nil
+	}
+	params := make([]abiParam, len(t.TupleElems))
+	for i, e := range t.TupleElems {
+		params[i] = abiParam{Name: t.TupleRawNames[i], Type: e.String(), Components: abiComponents(*e)}
+	}
+	return params
+}
+
+// stateMutability returns the mutability, including for legacy ABIs without it
+func stateMutability(m abi.Method) string {
+	switch {
+	case m.StateMutability != "":
+		return m.StateMutability
+	case m.Payable:
+		return "payable"
+	case m.Constant:
+		return "view"
+	}
+	return "nonpayable"
+}
+
+// methodEntry returns the entry of the function, constructor, fallback or receive function
+func methodEntry(m abi.Method) abiEntry {
+	e := abiEntry{
+		Name:            m.RawName,
+		Signature:       m.Sig,
+		StateMutability: stateMutability(m),
+		Inputs:          abiParams(m.Inputs),
+		Outputs:         abiParams(m.Outputs),
+	}
+	switch m.Type {
+	case abi.Constructor:
+		e.Type = "constructor"
+	case abi.Fallback:
+		e.Type, e.Signature = "fallback", "fallback()"
+	case abi.Receive:
+		e.Type, e.Signature = "receive", "receive()"
+	default:
+		e.Type, e.Selector = "function", fmt.Sprintf("0x%x", m.ID)
+	}
+	return e
+}
+
+// abiEntries returns the ABI's constructor, fallback and receive functions, then its functions, events and
+// errors, each ordered by signature
+func abiEntries(a *abi.ABI) []abiEntry {
+	var entries []abiEntry
+	if a.Constructor.StateMutability != "" || a.Constructor.Payable || len(a.Constructor.Inputs) > 0 {
+		e := methodEntry(a.Constructor)
+		e.Signature = tx.ConstructorSig(*a)
+		entries = append(entries, e)
+	}
+	if a.HasFallback() {
+		entries = append(entries, methodEntry(a.Fallback))
+	}
+	if a.HasReceive() {
+		entries = append(entries, methodEntry(a.Receive))
+	}
+
+	var functions, events, errs []abiEntry
+	for _, m := range a.Methods {
+		functions = append(functions, methodEntry(m))
+	}
+	for _, ev := range a.Events {
+		e := abiEntry{Type: "event", Name: ev.RawName, Signature: ev.Sig, Inputs: abiParams(ev.Inputs)}
+		if !ev.Anonymous {
+			e.Topic = ev.ID.Hex()
+		}
+		events = append(events, e)
+	}
+	for _, er := range a.Errors {
+		errs = append(errs, abiEntry{Type: "error", Name: er.Name, Signature: er.Sig, Selector: fmt.Sprintf("0x%x", er.ID[:4]), Inputs: abiParams(er.Inputs)})
+	}
+	for _, group := range [][]abiEntry{functions, events, errs} {
+		sort.Slice(group, func(i, j int) bool { return group[i].Signature < group[j].Signature })
+		entries = append(entries, group...)
+	}
+	return entries
+}
+
+// matchesEntry returns whether the entry has the name, signature, selector or topic
+func matchesEntry(e abiEntry, s string) bool {
+	if strings.Contains(s, "(") {
+		if m, err := parser.ParseSignature(s); err == nil {
+			s = m.Sig
+		}
+		return e.Signature == s || e.Signature == strings.Replace(s, " ", "", -1)
+	}
+	return e.Name == s || e.Type == s || strings.EqualFold(e.Selector, s) || strings.EqualFold(e.Topic, s)
+}
+
+// formatParams formats the params as `type name`, with tuples as their components
+func formatParams(params []abiParam) string {
+	s := make([]string, len(params))
+	for i, p := range params {
+		t := p.Type
+		if p.Components != nil {
+			// Keep the array suffix of arrays of tuples, eg. `(address,uint256)[]`
+			t = "(" + formatParams(p.Components) + ")" + t[strings.LastIndex(t, ")")+1:]
+		}
+		if p.Indexed {
+			t += " indexed"
+		}
+		s[i] = strings.TrimSpace(t + " " + p.Name)
+	}
+	return strings.Join(s, ", ")
+}
+
+// printABI prints the entries of the ABI, or the function signature, matching the optional name, signature or
+// selector, as text or JSON
+func printABI(w io.Writer) error {
+	var entries []abiEntry
+	if contractABI == nil {
+		m, err := parser.ParseSignature(args[0])
+		if err != nil {
+			return err
+		}
+		entries = []abiEntry{methodEntry(m)}
+		entries[0].StateMutability = ""
+	} else {
+		for _, e := range abiEntries(contractABI) {
+			if len(args) == 0 || matchesEntry(e, args[0]) {
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("'%s' not found in ABI", args[0])
+		}
+	}
+
+	if *outputFlag == "json" {
+		return printJSON(w, entries)
+	}
+	for i, e := range entries {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s %s", e.Type, e.Signature)
+		if e.StateMutability != "" {
+			fmt.Fprintf(w, " %s", e.StateMutability)
+		}
+		fmt.Fprintln(w)
+		if e.Selector != "" {
+			fmt.Fprintf(w, "  Selector: %s\n", e.Selector)
+		}
+		if e.Topic != "" {
+			fmt.Fprintf(w, "  Topic:    %s\n", e.Topic)
+		}
+		if len(e.Inputs) > 0 {
+			fmt.Fprintf(w, "  Inputs:   %s\n", formatParams(e.Inputs))
+		}
+		if len(e.Outputs) > 0 {
+			fmt.Fprintf(w, "  Outputs:  %s\n", formatParams(e.Outputs))
+		}
+	}
+	return nil
+}
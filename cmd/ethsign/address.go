@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/accounts"
+
+	"github.com/juztin/ethsign/signer"
+)
+
+// loadHDWallet reads the --mnemonic-file, prompting for its passphrase when --mnemonic-passphrase is given
+func loadHDWallet() (*signer.HDWallet, error) {
+	b, err := ioutil.ReadFile(mnemonicFlag.String())
+	if err != nil {
+		return nil, err
+	}
+	var p string
+	if *mnemonicPassFlag {
+		if p, err = readPassphrase("Mnemonic passphrase: "); err != nil {
+			return nil, err
+		}
+	}
+	return signer.NewHDWallet(string(b), p)
+}
+
+// hdPath returns the --hd-path, with the last component set to the --account-index, when given, plus offset.
+// The index can't overflow into the hardened bit, which would derive an unrelated key.
+func hdPath(offset uint) (accounts.DerivationPath, error) {
+	path, err := accounts.ParseDerivationPath(*hdPathFlag)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid HD path '%s': %w", *hdPathFlag, err)
+	}
+	last := len(path) - 1
+	hardened, index := path[last]&0x80000000, uint64(path[last]&^0x80000000)
+	if flagIsSet("account-index") {
+		if *accountIndexFlag >= 0x80000000 {
+			return nil, fmt.Errorf("Invalid account index '%d', must be less than 2147483648", *accountIndexFlag)
+		}
+		index = uint64(*accountIndexFlag)
+	}
+	if index += uint64(offset); index >= 0x80000000 {
+		return nil, fmt.Errorf("HD wallet index '%d' exceeds the last index, 2147483647", index)
+	}
+	path[last] = hardened | uint32(index)
+	return path, nil
+}
+
+// printAddresses prints the address of the signing key, or the first --count HD wallet addresses
+func printAddresses(w io.Writer) error {
+	if mnemonicFlag.String() == "" {
+		s, err := loadSigner()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, s.Address().Hex())
+		return nil
+	}
+
+	wallet, err := loadHDWallet()
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < *countFlag; i++ {
+		path, err := hdPath(i)
+		if err != nil {
+			return err
+		}
+		k, err := wallet.Derive(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%-24s %s\n", path, k.Address().Hex())
+	}
+	return nil
+}
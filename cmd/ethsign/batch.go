@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/juztin/ethsign/encoding"
+	"github.com/juztin/ethsign/ledger"
+	"github.com/juztin/ethsign/parser"
+	"github.com/juztin/ethsign/tx"
+)
+
+// batchTx is a signed manifest transaction, for --output json
+type batchTx struct {
+	Line int `json:"line"`
+	*txOutput
+}
+
+func validateBatchArgs() error {
+	if manifestFlag.String() == "" {
+		return errors.New("Must specify the manifest of transactions to sign [--manifest]")
+	} else if recipientFlag.IsSet() {
+		return errors.New("Recipients are given per manifest row, not by --to")
+	} else if len(args) > 0 {
+		return errors.New("Unexpected arguments, transactions are given by the manifest")
+	}
+	return nil
+}
+
+// parseFee parses the manifest fee, in Gwei by default, or returns the fallback when empty
+func parseFee(name, value string, fallback *big.Int) (*big.Int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := parser.ParseNumber(value, 9)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s '%s': %w", name, value, err)
+	}
+	return n, nil
+}
+
+// rowOptions returns the builder options for the manifest row, on top of the transaction flags
+func rowOptions(r encoding.ManifestRow, a *abi.ABI) ([]tx.Option, error) {
+	opts := txOptions()
+	if !common.IsHexAddress(r.To) {
+		return nil, fmt.Errorf("Invalid recipient '%s'", r.To)
+	}
+	opts = append(opts, tx.WithTo(common.HexToAddress(r.To)))
+
+	if r.Value != "" {
+		v, err := parser.ParseNumber(r.Value, 18)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value '%s': %w", r.Value, err)
+		}
+		opts = append(opts, tx.WithValue(v))
+	}
+	if r.GasLimit != "" {
+		n, err := parser.ParseNumber(r.GasLimit, 0)
+		if err != nil || !n.IsUint64() {
+			return nil, fmt.Errorf("Invalid gasLimit '%s'", r.GasLimit)
+		}
+		opts = append(opts, tx.WithGasLimit(n.Uint64()))
+	}
+
+	// Fees override the flags, but legacy and EIP-1559 fees can't be mixed
+	dynamic := maxFeeFlag.IsSet() || r.MaxFeePerGas != "" || r.MaxPriorityFeePerGas != ""
+	if dynamic && r.GasPrice != "" {
+		return nil, errors.New("Can't specify gasPrice with maxFeePerGas or maxPriorityFeePerGas")
+	} else if dynamic {
+		if !maxFeeFlag.IsSet() && (r.MaxFeePerGas == "" || r.MaxPriorityFeePerGas == "") {
+			return nil, errors.New("Must specify both maxFeePerGas and maxPriorityFeePerGas")
+		}
+		maxFee, err := parseFee("maxFeePerGas", r.MaxFeePerGas, maxFeeFlag.Value())
+		if err != nil {
+			return nil, err
+		}
+		maxPriorityFee, err := parseFee("maxPriorityFeePerGas", r.MaxPriorityFeePerGas, maxPriorityFeeFlag.Value())
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, tx.WithDynamicFees(maxFee, maxPriorityFee))
+	} else if r.GasPrice != "" {
+		price, err := parseFee("gasPrice", r.GasPrice, nil)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, tx.WithGasPrice(price))
+	}
+
+	kind := strings.ToLower(r.Kind)
+	if kind == "" && r.Method != "" {
+		kind = "call"
+	}
+	switch kind {
+	case "", "ether":
+		if r.Method != "" {
+			return nil, errors.New("Can't specify a method when sending ether")
+		} else if len(r.Args) > 1 {
+			return nil, errors.New("Can't supply multiple arguments/messages within a transaction")
+		}
+		data, err := etherInput(r.Args)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, tx.WithData(data))
+	case "call":
+		if r.Method == "" {
+			return nil, errors.New("Must specify the function signature, or ABI function name")
+		} else if a != nil {
+			opts = append(opts, tx.WithABIMethod(*a, r.Method, r.Args...))
+		} else if strings.Contains(r.Method, "(") {
+			opts = append(opts, tx.WithMethod(r.Method, r.Args...))
+		} else {
+			return nil, fmt.Errorf("Must specify an ABI for function '%s' [--abi, --artifact]", r.Method)
+		}
+	default:
+		return nil, fmt.Errorf("Invalid kind '%s', must be one of [ether, call]", r.Kind)
+	}
+
+	// Ensure the row builds, so nothing is signed when any row is invalid
+	b, err := tx.New(opts...)
+	if err == nil {
+		_, err = b.Build()
+	}
+	return opts, err
+}
+
+// signBatch signs every manifest transaction, with consecutive nonces, printing one raw transaction per line,
+// or a JSON array with --output json. Every row is parsed before the key is loaded.
+func signBatch(w io.Writer) error {
+	rows, err := encoding.ReadManifest(manifestFlag.String())
+	if err != nil {
+		return err
+	} else if len(rows) == 0 {
+		return errors.New("Manifest contains no transactions")
+	}
+	var errs []string
+	opts := make([][]tx.Option, len(rows))
+	for i, r := range rows {
+		if opts[i], err = rowOptions(r, contractABI); err != nil {
+			errs = append(errs, fmt.Sprintf("Line %d: %s", r.Line, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+
+	// Decrypt the key once, starting from --nonce, or the ledger's next nonce
+	s, err := loadSigner()
+	if err != nil {
+		return err
+	}
+	var l *ledger.Ledger
+	nonce := *nonceFlag
+	if *ledgerFlag {
+		if l, err = openLedger(); err != nil {
+			return err
+		}
+		defer l.Close()
+		if !flagIsSet("nonce") {
+			nonce = l.Next(chainFlag.Value(), s.Address())
+		}
+	}
+
+	signed := make([]batchTx, len(rows))
+	for i, r := range rows {
+		b, err := tx.New(append(opts[i], tx.WithNonce(nonce+uint64(i)), tx.WithSigner(s))...)
+		if err != nil {
+			return fmt.Errorf("Line %d: %w", r.Line, err)
+		}
+		t, raw, err := b.Sign()
+		if err != nil {
+			return fmt.Errorf("Line %d: %w", r.Line, err)
+		}
+		o, err := newTxOutput(t, raw, r.Method, contractABI)
+		if err != nil {
+			return fmt.Errorf("Line %d: %w", r.Line, err)
+		}
+		signed[i] = batchTx{r.Line, o}
+	}
+	if l != nil {
+		l.Used(chainFlag.Value(), s.Address(), nonce+uint64(len(rows)-1))
+		if err = l.Save(); err != nil {
+			return err
+		}
+	}
+
+	if *outputFlag == "json" {
+		return printJSON(w, signed)
+	}
+	for _, t := range signed {
+		fmt.Fprintln(w, t.Raw)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/juztin/ethsign/parser"
+)
+
+var txTypeNames = map[uint8]string{
+	types.LegacyTxType:     "legacy",
+	types.AccessListTxType: "access list",
+	types.DynamicFeeTxType: "dynamic fee",
+}
+
+// decodeRawTx decodes the given raw, signed, hex-string transaction
+func decodeRawTx(raw string) (*types.Transaction, error) {
+	raw = strings.TrimSpace(raw)
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(raw, "0x"), "0X"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid raw transaction: %w", err)
+	}
+	t := new(types.Transaction)
+	if err = t.UnmarshalBinary(b); err != nil {
+		return nil, fmt.Errorf("Invalid raw transaction: %w", err)
+	}
+	return t, nil
+}
+
+// txSender recovers the address which signed the transaction
+func txSender(t *types.Transaction) (common.Address, error) {
+	if !t.Protected() {
+		return types.Sender(types.HomesteadSigner{}, t)
+	}
+	return types.Sender(types.LatestSignerForChainID(t.ChainId()), t)
+}
+
+// decodeCallData decodes the call data using either the ABI, or the method signature
+func decodeCallData(data []byte, method string, a *abi.ABI) (string, []interface{}, error) {
+	if a == nil {
+		values, err := parser.DecodeMethod(method, data)
+		return strings.Replace(method, " ", "", -1), values, err
+	}
+	if len(data) < 4 {
+		return "", nil, errors.New("Call data is missing a function selector")
+	}
+	m, err := a.MethodById(data[:4])
+	if err != nil {
+		return "", nil, err
+	}
+	values, err := m.Inputs.Unpack(data[4:])
+	return m.Sig, values, err
+}
+
+func printTx(w io.Writer, t *types.Transaction, method string, a *abi.ABI) error {
+	from, err := txSender(t)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Type:        %d (%s)\n", t.Type(), txTypeNames[t.Type()])
+	fmt.Fprintf(w, "Chain ID:    %s\n", t.ChainId())
+	fmt.Fprintf(w, "Hash:        %s\n", t.Hash().Hex())
+	fmt.Fprintf(w, "From:        %s\n", from.Hex())
+	if t.To() == nil {
+		fmt.Fprintf(w, "To:          (contract creation)\n")
+		fmt.Fprintf(w, "Contract:    %s\n", crypto.CreateAddress(from, t.Nonce()).Hex())
+	} else {
+		fmt.Fprintf(w, "To:          %s\n", t.To().Hex())
+	}
+	fmt.Fprintf(w, "Nonce:       %d\n", t.Nonce())
+	fmt.Fprintf(w, "Gas Limit:   %d\n", t.Gas())
+	if t.Type() == types.DynamicFeeTxType {
+		fmt.Fprintf(w, "Max Fee:     %s wei\n", t.GasFeeCap())
+		fmt.Fprintf(w, "Max Tip:     %s wei\n", t.GasTipCap())
+	} else {
+		fmt.Fprintf(w, "Gas Price:   %s wei\n", t.GasPrice())
+	}
+	fmt.Fprintf(w, "Value:       %s wei\n", t.Value())
+	fmt.Fprintf(w, "Data:        0x%x\n", t.Data())
+	for _, t := range t.AccessList() {
+		fmt.Fprintf(w, "Access List: %s\n", t.Address.Hex())
+		for _, k := range t.StorageKeys {
+			fmt.Fprintf(w, "               %s\n", k.Hex())
+		}
+	}
+
+	// Decode call data, when we know how to
+	if t.To() == nil || len(t.Data()) == 0 || (method == "" && a == nil) {
+		return nil
+	}
+	sig, values, err := decodeCallData(t.Data(), method, a)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Method:      %s\n", sig)
+	for i := range values {
+		fmt.Fprintf(w, "  [%d]        %s\n", i, formatValue(values[i]))
+	}
+	return nil
+}
+
+// formatValue formats an unpacked ABI value for display
+func formatValue(v interface{}) string {
+	switch t := v.(type) {
+	case common.Address:
+		return t.Hex()
+	case *big.Int:
+		return t.String()
+	case []byte:
+		return fmt.Sprintf("0x%x", t)
+	case string:
+		return fmt.Sprintf("%q", t)
+	}
+	r := reflect.ValueOf(v)
+	switch r.Kind() {
+	case reflect.Struct:
+		s := make([]string, r.NumField())
+		for i := range s {
+			s[i] = formatValue(r.Field(i).Interface())
+		}
+		return "(" + strings.Join(s, ",") + ")"
+	case reflect.Array, reflect.Slice:
+		// Fixed bytes, eg. `bytes32`
+		if r.Type().Elem().Kind() == reflect.Uint8 && r.Kind() == reflect.Array {
+			b := make([]byte, r.Len())
+			reflect.Copy(reflect.ValueOf(b), r)
+			return fmt.Sprintf("0x%x", b)
+		}
+		s := make([]string, r.Len())
+		for i := range s {
+			s[i] = formatValue(r.Index(i).Interface())
+		}
+		return "[" + strings.Join(s, ",") + "]"
+	}
+	return fmt.Sprint(v)
+}
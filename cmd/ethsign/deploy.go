@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/juztin/ethsign/tx"
+)
+
+func validateCreate2Args() error {
+	if !*create2Flag {
+		if *saltFlag != "" || flagIsSet("factory") {
+			return errors.New("Salt, and factory, require a CREATE2 deployment [--create2]")
+		}
+		return nil
+	} else if cmd != DEPLOY {
+		return errors.New("CREATE2 is only for contract deployment")
+	} else if *saltFlag == "" {
+		return errors.New("Must specify the CREATE2 salt [--salt]")
+	} else if !factoryFlag.IsSet() {
+		return errors.New("Must specify a valid CREATE2 factory address [--factory]")
+	}
+
+	// Salts must be exactly 32 bytes, a missing byte would deploy to a different address
+	h := strings.TrimPrefix(strings.TrimPrefix(*saltFlag, "0x"), "0X")
+	b, err := hex.DecodeString(h)
+	if err != nil || len(b) != common.HashLength {
+		return fmt.Errorf("Invalid salt '%s', must be 32 hex-encoded bytes", *saltFlag)
+	}
+	salt = common.BytesToHash(b)
+	return nil
+}
+
+// contractAddress returns the address of the contract the transaction deploys, either by contract creation from
+// the sender and nonce, or by CREATE2 through the factory
+func contractAddress(t *types.Transaction, from common.Address) *common.Address {
+	if t.To() == nil {
+		addr := crypto.CreateAddress(from, t.Nonce())
+		return &addr
+	} else if !*create2Flag || *t.To() != factoryFlag.Value || len(t.Data()) < common.HashLength {
+		return nil
+	}
+	addr := tx.Create2Address(*t.To(), common.BytesToHash(t.Data()[:common.HashLength]), t.Data()[common.HashLength:])
+	return &addr
+}
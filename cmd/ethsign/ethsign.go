@@ -1,58 +1,105 @@
 package main
 
 import (
-	"bytes"
-	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"math/big"
 	"os"
+	"strings"
 	"syscall"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/juztin/ethsign/encoding"
 	"github.com/juztin/ethsign/flags"
+	"github.com/juztin/ethsign/ledger"
 	"github.com/juztin/ethsign/parser"
+	"github.com/juztin/ethsign/signer"
+	"github.com/juztin/ethsign/tx"
 )
 
 type command int
-type signFunc func(*types.Transaction, *big.Int, string) (*types.Transaction, error)
 
 const (
-	CALL = iota
+	ABI = iota
+	ADDRESS
+	BATCH
+	CALL
+	DECODE
 	DEPLOY
 	ETHER
+	NONCE
+	PERMIT
+	SIGN_MESSAGE
+	SIGN_TYPED
+	VERIFY
 )
 
+const commands = "[ether, call, deploy, decode, address, sign-message, sign-typed, permit, verify, nonce, batch, abi]"
+
 var (
 	// args
 	args       []string
 	cmd        command
-	keyPath    string
 	method     string
 	methodArgs []string
-	signFn     signFunc
+
+	accessList  types.AccessList
+	artifact    *tx.Artifact
+	contractABI *abi.ABI
+	salt        common.Hash
 
 	// flags
-	abiFlag       flags.FileFlag
-	binFlag       flags.FileFlag
-	keyFlag       flags.FileFlag
-	keystoreFlag  flags.FileFlag
-	recipientFlag flags.AddressFlag
-
-	chainFlag    = flags.BigInt(big.NewInt(1337))
-	gasPriceFlag = flags.Ether(big.NewInt(1), flags.GWEI)
-	gasLimitFlag = flag.Uint64("gasLimit", 100000, "The gas limit, in Gwei")
-	helpFlag     = flag.Bool("help", false, "Print ethsign usage")
-	nonceFlag    = flag.Uint64("nonce", 0, "Next nonce for the address signing the transaction")
-	valueFlag    = flags.Ether(big.NewInt(0), flags.ETHER)
+	abiFlag        flags.FileFlag
+	accessListFlag flags.FileFlag
+	artifactFlag   flags.FileFlag
+	binFlag        flags.FileFlag
+	dataFlag       flags.FileFlag
+	expectFlag     flags.AddressFlag
+	keyFlag        flags.FileFlag
+	keystoreFlag   flags.FileFlag
+	librariesFlag  flags.FileFlag
+	linkFlag       flags.LibrariesFlag
+	manifestFlag   flags.FileFlag
+	messageFlag    flags.FileFlag
+	mnemonicFlag   flags.FileFlag
+	recipientFlag  flags.AddressFlag
+	spenderFlag    flags.AddressFlag
+	tokenFlag      flags.AddressFlag
+
+	accountIndexFlag   = flag.Uint("account-index", 0, "The HD wallet account index, replacing the last component of --hd-path")
+	amountFlag         = flag.String("amount", "", "The amount in token base units, or max, for permit")
+	calldataFlag       = flag.Bool("calldata", false, "Print the permit call data, for permit")
+	chainFlag          = flags.BigInt(big.NewInt(1337))
+	contractFlag       = flag.String("contract", "", "The contract name, within a solc combined-json --artifact")
+	create2Flag        = flag.Bool("create2", false, "Deploy the contract with CREATE2, through the --factory")
+	countFlag          = flag.Uint("count", 5, "The number of HD wallet addresses to list")
+	deadlineFlag       = flags.BigInt(big.NewInt(0))
+	factoryFlag        = flags.Address(tx.DeterministicDeployer)
+	gasPriceFlag       = flags.Ether(big.NewInt(1), flags.GWEI)
+	gasLimitFlag       = flag.Uint64("gasLimit", 100000, "The gas limit, in Gwei")
+	hexFlag            = flag.Bool("hex", false, "The message is hex-encoded bytes, for sign-message")
+	hdPathFlag         = flag.String("hd-path", "m/44'/60'/0'/0/0", "The BIP-32 HD wallet derivation path")
+	helpFlag           = flag.Bool("help", false, "Print ethsign usage")
+	keyEnvFlag         = flag.String("key-env", "", "Environment variable containing the hex private key")
+	ledgerFlag         = flag.Bool("ledger", false, "Use, and update, the offline nonce ledger")
+	ledgerFileFlag     = flag.String("ledger-file", "", "The offline nonce ledger filepath (default ~/.ethsign/nonces.json)")
+	maxFeeFlag         = flags.Ether(big.NewInt(0), flags.GWEI)
+	maxPriorityFeeFlag = flags.Ether(big.NewInt(0), flags.GWEI)
+	mnemonicPassFlag   = flag.Bool("mnemonic-passphrase", false, "Prompt for the BIP-39 mnemonic passphrase")
+	nameFlag           = flag.String("name", "", "The token's EIP-712 domain name, for permit")
+	nonceFlag          = flag.Uint64("nonce", 0, "Next nonce for the address signing the transaction")
+	outputFlag         = flag.String("output", "hex", "The signed transaction output format [hex, json]")
+	padBytesFlag       = flag.Bool("padBytes", false, "Right-pad fixed-size bytes arguments shorter than their size with zeros")
+	saltFlag           = flag.String("salt", "", "The CREATE2 salt, as 32 hex-encoded bytes")
+	signatureFlag      = flag.String("signature", "", "The hex signature of the message, or typed data, for verify")
+	valueFlag          = flags.Ether(big.NewInt(0), flags.ETHER)
+	versionFlag        = flag.String("version", "1", "The token's EIP-712 domain version, for permit")
+	vFlag              = flag.Uint("v", 27, "The signature V convention, 27 (27/28) or 0 (0/1)")
 )
 
 func init() {
@@ -60,12 +107,27 @@ func init() {
 	//flag.StringVar(keyFile, "k", "", "Private key file path")
 
 	flag.Var(&abiFlag, "abi", "Contract ABI file")
+	flag.Var(&accessListFlag, "accessList", "EIP-2930 access list file, as returned by eth_createAccessList")
+	flag.Var(&artifactFlag, "artifact", "Hardhat, Foundry or solc combined-json contract artifact file")
 	flag.Var(&binFlag, "bin", "Contract BIN file, for contract deployments")
 	flag.Var(&chainFlag, "chain", "Chain ID (default l337)")
+	flag.Var(&deadlineFlag, "deadline", "The permit deadline, as a unix timestamp, for permit")
+	flag.Var(&dataFlag, "data", "EIP-712 typed data filepath, for sign-typed")
+	flag.Var(&expectFlag, "expect", "The expected signer address, for verify")
+	flag.Var(&factoryFlag, "factory", "The CREATE2 deployer factory address (default 0x4e59b44847b379578588920ca78fbf26c0b4956c)")
 	flag.Var(&gasPriceFlag, "gasPrice", "The gas price to use, in Gwei (default 1)")
-	flag.Var(&keyFlag, "key", "Private key filepath")
+	flag.Var(&keyFlag, "key", "Private hex key filepath")
 	flag.Var(&keystoreFlag, "keystore", "Private go-ethereum keystore filepath")
+	flag.Var(&librariesFlag, "libraries", "Library addresses JSON file, for deploy")
+	flag.Var(&linkFlag, "link", "Library address, as Name=0xaddress, for deploy (repeatable)")
+	flag.Var(&manifestFlag, "manifest", "CSV, or JSON, manifest of transactions, for batch")
+	flag.Var(&maxFeeFlag, "maxFeePerGas", "The EIP-1559 max fee per gas, in Gwei")
+	flag.Var(&maxPriorityFeeFlag, "maxPriorityFeePerGas", "The EIP-1559 max priority fee per gas, in Gwei")
+	flag.Var(&messageFlag, "message-file", "Message filepath, for sign-message")
+	flag.Var(&mnemonicFlag, "mnemonic-file", "BIP-39 mnemonic filepath")
 	flag.Var(&recipientFlag, "to", "The recipient address to send the transaction to")
+	flag.Var(&spenderFlag, "spender", "The spender address, for permit")
+	flag.Var(&tokenFlag, "token", "The token address, for permit")
 	flag.Var(&valueFlag, "value", "The amount of Ether to send with the transaction (default 0)")
 
 	//pos := 0
@@ -79,32 +141,61 @@ func init() {
 	//	}
 	//}
 
+	// Arguments end at the first flag, negative numbers aren't flags
 	pos := 1
 	for ; pos < len(os.Args); pos++ {
-		if len(os.Args[pos]) > 0 && os.Args[pos][0] == '-' {
+		a := os.Args[pos]
+		if len(a) > 0 && a[0] == '-' && (len(a) == 1 || a[1] < '0' || a[1] > '9') {
 			break
 		}
 	}
 	flag.Usage = usage
 	flag.Parse()
 	if pos < 2 {
-		checkErr(errors.New("Missing required command: [ether, call, deploy]"))
+		checkErr(errors.New("Missing required command: " + commands))
 	}
 	switch os.Args[1] {
+	case "abi":
+		cmd = ABI
+		break
+	case "address":
+		cmd = ADDRESS
+		break
+	case "batch":
+		cmd = BATCH
+		break
 	case "call":
 		cmd = CALL
 		break
+	case "decode":
+		cmd = DECODE
+		break
 	case "deploy":
 		cmd = DEPLOY
 		break
 	case "ether":
 		cmd = ETHER
 		break
+	case "nonce":
+		cmd = NONCE
+		break
+	case "permit":
+		cmd = PERMIT
+		break
+	case "sign-message":
+		cmd = SIGN_MESSAGE
+		break
+	case "sign-typed":
+		cmd = SIGN_TYPED
+		break
+	case "verify":
+		cmd = VERIFY
+		break
 	case "help":
 		flag.Usage()
 		break
 	default:
-		checkErr(fmt.Errorf("Invalid command: '%s', must be one of [ether, call, deploy]", os.Args[1]))
+		checkErr(fmt.Errorf("Invalid command: '%s', must be one of %s", os.Args[1], commands))
 	}
 	if pos > 2 {
 		args = os.Args[2:pos]
@@ -120,8 +211,36 @@ func checkErr(err error) {
 	os.Exit(1)
 }
 
+// flagIsSet returns whether the flag was given on the command line
+func flagIsSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
+
 func usage() {
-	fmt.Println(USAGE)
+	fmt.Print(USAGE)
+}
+
+// validateKeyArgs ensures exactly one of key, keystore, key environment variable or mnemonic is given
+func validateKeyArgs() error {
+	keys := 0
+	for _, k := range []string{keyFlag.String(), keystoreFlag.String(), *keyEnvFlag, mnemonicFlag.String()} {
+		if k != "" {
+			keys++
+		}
+	}
+	if keys == 0 {
+		return errors.New("Must specify a key [--key, --keystore, --key-env, --mnemonic-file]")
+	} else if keys > 1 {
+		return errors.New("Must specify only one of [--key, --keystore, --key-env, --mnemonic-file]")
+	}
+	if mnemonicFlag.String() == "" && (flagIsSet("hd-path") || flagIsSet("account-index") || *mnemonicPassFlag) {
+		return errors.New("HD wallet flags require a mnemonic [--mnemonic-file]")
+	}
+	return nil
 }
 
 func validateArgs() error {
@@ -129,51 +248,102 @@ func validateArgs() error {
 		flag.Usage()
 		os.Exit(0)
 	}
+	parser.PadBytes = *padBytesFlag
+	if *outputFlag != "hex" && *outputFlag != "json" {
+		return fmt.Errorf("Invalid output '%s', must be one of [hex, json]", *outputFlag)
+	}
 
-	// Validate key/keystore
-	keyPath = keyFlag.String()
-	if keyPath == "" {
-		return errors.New("Must specify key, or keystore file [--key]")
+	// Decoding only requires the raw transaction, and optionally a method signature
+	if cmd == DECODE {
+		if len(args) == 0 {
+			return errors.New("Must specify the raw transaction to decode")
+		} else if len(args) > 2 {
+			return errors.New("Too many arguments, expected a raw transaction and an optional function signature")
+		}
+		return loadContract()
 	}
-	fi, err := os.Stat(keyPath)
-	if err != nil {
-		return fmt.Errorf("Must specify a valid key, or keystore file, %v", err)
+
+	// Inspecting an ABI only requires the ABI, or a function signature
+	if cmd == ABI {
+		if err := loadContract(); err != nil {
+			return err
+		}
+		return validateABIArgs()
 	}
-	if fi.Size() == 0x40 {
-		signFn = signTxWithKey
-	} else {
-		signFn = signTxWithKeystore
+
+	// Verifying only requires the signed transaction, message or typed data
+	if cmd == VERIFY {
+		return validateVerifyArgs()
+	}
+
+	// Showing the nonce ledger doesn't require a key
+	if cmd == NONCE {
+		return validateNonceArgs()
+	}
+
+	if err := validateKeyArgs(); err != nil {
+		return err
+	}
+	switch cmd {
+	case ADDRESS:
+		return nil
+	case SIGN_MESSAGE:
+		return validateMessageArgs()
+	case SIGN_TYPED:
+		return validateTypedArgs()
+	case PERMIT:
+		return validatePermitArgs()
+	}
+
+	// Ensure EIP-1559 fees are given together, and not mixed with a legacy gas price
+	if maxFeeFlag.IsSet() || maxPriorityFeeFlag.IsSet() {
+		if !maxFeeFlag.IsSet() || !maxPriorityFeeFlag.IsSet() {
+			return errors.New("Must specify both --maxFeePerGas and --maxPriorityFeePerGas")
+		} else if gasPriceFlag.IsSet() {
+			return errors.New("Can't specify --gasPrice with --maxFeePerGas or --maxPriorityFeePerGas")
+		}
 	}
 
-	// Ensure `Value` is non-negative
-	if valueFlag.Value().Cmp(big.NewInt(0)) < 0 {
-		return errors.New("Can't send negative Ether")
+	// Read and validate the access list
+	var err error
+	if accessListFlag.String() != "" {
+		accessList, err = encoding.ReadAccessList(accessListFlag.String())
+		if err != nil {
+			return err
+		}
 	}
 
-	// Ensure `Nonce` is non-negative
-	if *nonceFlag < 0 {
-		return errors.New("Nonce must bea non-negative")
+	// Read the contract ABI, and bytecode, from either --abi and --bin, or the --artifact
+	if err = loadContract(); err != nil {
+		return err
+	}
+	if err = validateCreate2Args(); err != nil {
+		return err
 	}
 
 	switch cmd {
+	case BATCH:
+		return validateBatchArgs()
 	case CALL:
 		if len(args) == 0 {
-			if abiFlag.String() == "" {
-				err = errors.New("Must specify function signature")
-			} else {
-				err = errors.New("Must specify ABI function name")
+			if contractABI == nil {
+				return errors.New("Must specify function signature")
 			}
+			return errors.New("Must specify ABI function name")
 		}
 		method = args[0]
 		methodArgs = args[1:]
 		break
 	case DEPLOY:
-		if binFlag.String() == "" {
-			err = errors.New("Must specify bin file for contract deployment [--bin]")
+		if binFlag.String() == "" && artifact == nil {
+			err = errors.New("Must specify bin file, or artifact, for contract deployment [--bin, --artifact]")
 		} else if recipientFlag.IsSet() {
 			err = errors.New("Recipient can't be set for contract deployment")
 		}
-		if len(args) == 0 {
+		// With an ABI every argument is a constructor argument, unless an explicit constructor signature is given
+		if contractABI != nil && (len(args) == 0 || !strings.HasPrefix(args[0], "constructor(")) {
+			methodArgs = args
+		} else if len(args) == 0 {
 			method = "constructor()"
 		} else {
 			method = args[0]
@@ -191,109 +361,188 @@ func validateArgs() error {
 	return err
 }
 
-func readABI(abiFile string) (abi.ABI, error) {
-	r, err := os.Open(abiFile)
-	if err != nil {
-		return abi.ABI{}, err
+func etherInput(args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, nil
 	}
-	return abi.JSON(r)
+	return []byte(args[0]), nil
 }
 
-func readBin(binFile string) ([]byte, error) {
-	b, err := ioutil.ReadFile(binFile)
-	if err != nil {
-		return nil, err
-	}
-	return hex.DecodeString(string(b))
+// readPassphrase prompts for, and reads, a passphrase from the terminal
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	p, err := terminal.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintln(os.Stderr)
+	return string(p), err
 }
 
-func callInputABI(methodSig string, args []string, abiFile string) ([]byte, error) {
-	a, err := readABI(abiFile)
-	if err != nil {
-		return nil, err
+// loadSigner returns the Signer for whichever of --key, --keystore, --key-env or --mnemonic-file was given
+func loadSigner() (signer.Signer, error) {
+	switch {
+	case keyFlag.String() != "":
+		return signer.FromFile(keyFlag.String())
+	case keystoreFlag.String() != "":
+		p, err := readPassphrase("Passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		return signer.FromKeystore(keystoreFlag.String(), p)
+	case mnemonicFlag.String() != "":
+		w, err := loadHDWallet()
+		if err != nil {
+			return nil, err
+		}
+		path, err := hdPath(0)
+		if err != nil {
+			return nil, err
+		}
+		return w.Derive(path)
 	}
+	return signer.FromEnv(*keyEnvFlag)
+}
 
-	// Ensure the given function name exists within the ABI
-	var m abi.Method
-	ok := true
-	if methodSig == "" {
-		m = a.Constructor
-	} else if m, ok = a.Methods[methodSig]; !ok {
-		return nil, errors.New("missing function in ABI")
+// txOptions returns the builder options for the transaction flags
+func txOptions() []tx.Option {
+	opts := []tx.Option{
+		tx.WithChainID(chainFlag.Value()),
+		tx.WithNonce(*nonceFlag),
+		tx.WithGasLimit(*gasLimitFlag),
+		tx.WithValue(valueFlag.Value()),
+		tx.WithAccessList(accessList),
 	}
-
-	// Convert args to matching types
-	funcArgs, err := encoding.DecodeArgs(m.Inputs, args...)
-	if err != nil {
-		return nil, err
+	if maxFeeFlag.IsSet() {
+		opts = append(opts, tx.WithDynamicFees(maxFeeFlag.Value(), maxPriorityFeeFlag.Value()))
+	} else {
+		opts = append(opts, tx.WithGasPrice(gasPriceFlag.Value()))
 	}
-
-	// Generate packed call
-	input, err := a.Pack(methodSig, funcArgs...)
-	return input, err
+	return opts
 }
 
-func deployInputABI(methodSig string, args []string, binFile, abiFile string) ([]byte, error) {
-	input, err := callInputABI("", args, abiFile)
-	if err != nil {
-		return input, err
+// loadContract reads the contract ABI from --abi, or the ABI and bytecode from --artifact
+func loadContract() error {
+	if artifactFlag.String() != "" {
+		if abiFlag.String() != "" || binFlag.String() != "" {
+			return errors.New("Can't specify --abi or --bin with --artifact")
+		}
+		a, err := tx.ReadArtifact(artifactFlag.String(), *contractFlag)
+		if err != nil {
+			return err
+		}
+		artifact, contractABI = a, &a.ABI
+	} else if *contractFlag != "" {
+		return errors.New("Contract name requires an artifact [--artifact]")
+	} else if abiFlag.String() != "" {
+		a, err := tx.ReadABI(abiFlag.String())
+		if err != nil {
+			return err
+		}
+		contractABI = &a
 	}
-	b, err := readBin(binFile)
-	return append(b, input...), err
+	return nil
 }
 
-func deployInputRaw(methodSig string, args []string, binFile string) ([]byte, error) {
-	input, err := parser.ParseConstructor(methodSig, args)
-	if err != nil {
-		return input, err
+// libraries returns the library addresses to link, from --libraries, and then --link
+func libraries() (map[string]common.Address, error) {
+	libs := make(map[string]common.Address)
+	if librariesFlag.String() != "" {
+		var err error
+		if libs, err = encoding.ReadLibraries(librariesFlag.String()); err != nil {
+			return nil, err
+		}
 	}
-	b, err := readBin(binFile)
-	return append(b, input...), err
-}
-
-func etherInput(args []string) ([]byte, error) {
-	if len(args) == 0 {
-		return nil, nil
+	for name, addr := range linkFlag.Value {
+		libs[name] = addr
 	}
-	return []byte(args[0]), nil
+	return libs, nil
 }
 
-func signTxWithKey(tx *types.Transaction, chainID *big.Int, keyPath string) (*types.Transaction, error) {
-	// Read keystore file
-	b, err := ioutil.ReadFile(keyPath)
-	if err != nil {
-		return nil, err
+// validateConstructor ensures an explicit constructor signature matches the ABI's, and that ether is only
+// sent to a payable constructor
+func validateConstructor(a abi.ABI) error {
+	if method != "" {
+		// Compare canonical signatures, eg. `constructor(uint)` is `constructor(uint256)`
+		m, err := parser.ParseSignature(method)
+		if err != nil {
+			return err
+		}
+		if sig := tx.ConstructorSig(a); m.Sig != sig {
+			return fmt.Errorf("Constructor signature '%s' doesn't match the ABI's '%s'", method, sig)
+		}
 	}
-
-	// Convert to ECDSA
-	k, err := crypto.HexToECDSA(string(b))
-	if err != nil {
-		return nil, err
+	if valueFlag.Value().Sign() > 0 && !a.Constructor.IsPayable() {
+		return errors.New("Constructor isn't payable, can't send ether with the deployment [--value]")
 	}
-	return types.SignTx(tx, types.NewEIP155Signer(chainID), k)
+	return nil
 }
 
-func signTxWithKeystore(tx *types.Transaction, chainID *big.Int, keyPath string) (*types.Transaction, error) {
-	// Read keystore file
-	b, err := ioutil.ReadFile(keyPath)
-	if err != nil {
-		return nil, err
+// dataOption returns the builder option for the command's transaction data
+func dataOption() tx.Option {
+	var data []byte
+	var err error
+	switch cmd {
+	case CALL:
+		if contractABI == nil {
+			return tx.WithMethod(method, methodArgs...)
+		}
+		return tx.WithABIMethod(*contractABI, method, methodArgs...)
+	case DEPLOY:
+		libs, err := libraries()
+		checkErr(err)
+		var bin []byte
+		if artifact != nil {
+			bin, err = artifact.Link(libs)
+		} else {
+			bin, err = tx.ReadLinkedBin(binFlag.String(), libs)
+		}
+		checkErr(err)
+		if contractABI == nil {
+			data, err = tx.DeployData(bin, method, methodArgs...)
+		} else {
+			checkErr(validateConstructor(*contractABI))
+			data, err = tx.ABIDeployData(*contractABI, bin, methodArgs...)
+		}
+		checkErr(err)
+		if *create2Flag {
+			return tx.WithCreate2(factoryFlag.Value, salt, data)
+		}
+		return tx.WithDeployment(data)
 	}
+	data, err = etherInput(args)
+	checkErr(err)
+	return tx.WithData(data)
+}
 
-	// Prompt for key password
-	fmt.Fprint(os.Stderr, "Passphrase: ")
-	p, err := terminal.ReadPassword(int(syscall.Stdin))
+// signTx signs the transaction, using, and updating, the next nonce from the ledger. The ledger is always
+// closed, releasing its lock, before returning.
+func signTx(s signer.Signer, opts []tx.Option) (*types.Transaction, []byte, error) {
+	var l *ledger.Ledger
+	var err error
+	nonce := *nonceFlag
+	if *ledgerFlag {
+		if l, err = openLedger(); err != nil {
+			return nil, nil, err
+		}
+		defer l.Close()
+		if !flagIsSet("nonce") {
+			nonce = l.Next(chainFlag.Value(), s.Address())
+			opts = append(opts, tx.WithNonce(nonce))
+		}
+	}
+	b, err := tx.New(append(opts, tx.WithSigner(s))...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	fmt.Println()
-
-	// Decrypt and sign
-	k, err := keystore.DecryptKey(b, string(p))
+	t, rawTx, err := b.Sign()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if l != nil {
+		l.Used(chainFlag.Value(), s.Address(), nonce)
+		if err = l.Save(); err != nil {
+			return nil, nil, err
+		}
 	}
-	return types.SignTx(tx, types.NewEIP155Signer(chainID), k.PrivateKey)
+	return t, rawTx, nil
 }
 
 func main() {
@@ -304,40 +553,72 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create transaction
-	var data []byte
-	var tx *types.Transaction
 	switch cmd {
-	case CALL:
-		if abiFlag.String() == "" {
-			data, err = parser.ParseMethod(method, methodArgs)
-		} else {
-			data, err = callInputABI(method, methodArgs, abiFlag.String())
-		}
-		tx = types.NewTransaction(*nonceFlag, recipientFlag.Value, valueFlag.Value(), *gasLimitFlag, gasPriceFlag.Value(), data)
-		break
-	case DEPLOY:
-		if abiFlag.String() == "" {
-			data, err = deployInputRaw(method, methodArgs, binFlag.String())
-		} else {
-			data, err = deployInputABI(method, methodArgs, binFlag.String(), abiFlag.String())
+	case ABI:
+		// Print the ABI's functions, constructor, events and errors
+		checkErr(printABI(os.Stdout))
+		return
+	case ADDRESS:
+		// Print the signing address, or HD wallet addresses
+		checkErr(printAddresses(os.Stdout))
+		return
+	case DECODE:
+		// Decode, and print, a raw transaction
+		t, err := decodeRawTx(args[0])
+		checkErr(err)
+		if len(args) > 1 {
+			method = args[1]
 		}
-		tx = types.NewContractCreation(*nonceFlag, valueFlag.Value(), *gasLimitFlag, gasPriceFlag.Value(), data)
-		break
-	case ETHER:
-		data, err = etherInput(args)
-		tx = types.NewTransaction(*nonceFlag, recipientFlag.Value, valueFlag.Value(), *gasLimitFlag, gasPriceFlag.Value(), data)
-		break
+		checkErr(printTx(os.Stdout, t, method, contractABI))
+		return
+	case SIGN_MESSAGE:
+		// Sign, and print, an EIP-191 message signature
+		checkErr(signMessage(os.Stdout))
+		return
+	case SIGN_TYPED:
+		// Sign, and print, an EIP-712 typed data signature
+		checkErr(signTyped(os.Stdout))
+		return
+	case PERMIT:
+		// Sign, and print, EIP-2612 permit signature components
+		checkErr(signPermit(os.Stdout))
+		return
+	case VERIFY:
+		// Recover, print and optionally check, the signer
+		checkErr(verify(os.Stdout))
+		return
+	case NONCE:
+		// Show, set or reset, the next nonce within the ledger
+		checkErr(nonceLedger(os.Stdout))
+		return
+	case BATCH:
+		// Sign, and print, every transaction within the manifest
+		checkErr(signBatch(os.Stdout))
+		return
+	}
+
+	// Create transaction
+	opts := txOptions()
+	if cmd != DEPLOY {
+		opts = append(opts, tx.WithTo(recipientFlag.Value))
 	}
+	opts = append(opts, dataOption())
 
 	// Sign transaction
+	s, err := loadSigner()
 	checkErr(err)
-	tx, err = signFn(tx, chainFlag.Value(), keyPath)
+	t, rawTx, err := signTx(s, opts)
 	checkErr(err)
 
-	// Print raw, signed, hex-string transaction
-	t := types.Transactions{tx}
-	rawTx := new(bytes.Buffer)
-	t.EncodeIndex(0, rawTx)
+	// Print the signed transaction as JSON, or the raw, signed, hex-string transaction
+	if *outputFlag == "json" {
+		o, err := newTxOutput(t, rawTx, method, contractABI)
+		checkErr(err)
+		checkErr(printJSON(os.Stdout, o))
+		return
+	}
+	if addr := contractAddress(t, s.Address()); addr != nil {
+		fmt.Fprintf(os.Stderr, "Contract address: %s\n", addr.Hex())
+	}
 	fmt.Printf("0x%x", rawTx)
 }
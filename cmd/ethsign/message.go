@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+func validateV() error {
+	if *vFlag != 0 && *vFlag != 27 {
+		return errors.New("Signature V must be either 27, or 0 [--v]")
+	}
+	return nil
+}
+
+func validateMessageArgs() error {
+	if err := validateV(); err != nil {
+		return err
+	}
+	if messageFlag.String() == "" && len(args) != 1 {
+		return errors.New("Must specify a single message, or message file [--message-file]")
+	} else if messageFlag.String() != "" && len(args) > 0 {
+		return errors.New("Can't specify both a message, and message file [--message-file]")
+	}
+	return nil
+}
+
+// readMessage returns the message argument, or --message-file contents, hex-decoding it when --hex is given
+func readMessage() ([]byte, error) {
+	var msg []byte
+	if messageFlag.String() == "" {
+		msg = []byte(args[0])
+	} else {
+		b, err := ioutil.ReadFile(messageFlag.String())
+		if err != nil {
+			return nil, err
+		}
+		msg = b
+	}
+	if !*hexFlag {
+		return msg, nil
+	}
+	h := strings.TrimSpace(string(msg))
+	if strings.HasPrefix(h, "0x") || strings.HasPrefix(h, "0X") {
+		h = h[2:]
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid hex message: %w", err)
+	}
+	return b, nil
+}
+
+// signMessage prints the 65-byte [R || S || V] EIP-191 `personal_sign` signature of the message
+func signMessage(w io.Writer) error {
+	msg, err := readMessage()
+	if err != nil {
+		return err
+	}
+	s, err := loadSigner()
+	if err != nil {
+		return err
+	}
+	sig, err := s.SignMessage(msg)
+	if err != nil {
+		return err
+	}
+	sig[64] += byte(*vFlag)
+	fmt.Fprintf(w, "0x%x", sig)
+	return nil
+}
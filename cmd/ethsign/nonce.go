@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/juztin/ethsign/ledger"
+	"github.com/juztin/ethsign/signer"
+)
+
+func validateNonceArgs() error {
+	if len(args) == 0 {
+		return errors.New("Must specify a nonce ledger action [show, set, reset]")
+	}
+	switch args[0] {
+	case "show":
+		if len(args) > 1 {
+			return errors.New("Unexpected arguments, expected 'show'")
+		}
+		if !keyGiven() {
+			return nil
+		}
+	case "set":
+		if len(args) != 2 {
+			return errors.New("Must specify the next nonce, 'set <nonce>'")
+		} else if _, err := strconv.ParseUint(args[1], 10, 64); err != nil {
+			return fmt.Errorf("Invalid nonce '%s'", args[1])
+		}
+	case "reset":
+		if len(args) > 1 {
+			return errors.New("Unexpected arguments, expected 'reset'")
+		}
+	default:
+		return fmt.Errorf("Invalid nonce ledger action '%s', must be one of [show, set, reset]", args[0])
+	}
+	return validateKeyArgs()
+}
+
+// keyGiven reports whether any of the key flags were given
+func keyGiven() bool {
+	return keyFlag.String() != "" || keystoreFlag.String() != "" || *keyEnvFlag != "" || mnemonicFlag.String() != ""
+}
+
+// openLedger opens the --ledger-file, or the default ledger
+func openLedger() (*ledger.Ledger, error) {
+	path := *ledgerFileFlag
+	if path == "" {
+		var err error
+		if path, err = ledger.DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+	return ledger.Open(path)
+}
+
+// nonceLedger shows, sets or resets, the next nonce of the signing address on the chain.
+// Without a key, show lists every address on every chain.
+func nonceLedger(w io.Writer) error {
+	// Load the key before locking the ledger, so other signers aren't blocked by the passphrase prompt
+	var s signer.Signer
+	var err error
+	if args[0] != "show" || keyGiven() {
+		if s, err = loadSigner(); err != nil {
+			return err
+		}
+	}
+	l, err := openLedger()
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	if s == nil {
+		for _, e := range l.Entries() {
+			fmt.Fprintf(w, "%-8s %s %d\n", e.ChainID, e.Address.Hex(), e.Nonce)
+		}
+		return nil
+	}
+
+	chainID, addr := chainFlag.Value(), s.Address()
+	switch args[0] {
+	case "set":
+		nonce, _ := strconv.ParseUint(args[1], 10, 64)
+		l.Set(chainID, addr, nonce)
+	case "reset":
+		l.Reset(chainID, addr)
+	}
+	if args[0] != "show" {
+		if err = l.Save(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "%-8s %s %d\n", chainID, addr.Hex(), l.Next(chainID, addr))
+	return nil
+}
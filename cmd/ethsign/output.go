@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// txOutput is a signed transaction, for --output json
+type txOutput struct {
+	Raw                  string           `json:"raw"`
+	Hash                 string           `json:"hash"`
+	From                 string           `json:"from"`
+	ChainID              string           `json:"chainId"`
+	Type                 uint8            `json:"type"`
+	Nonce                uint64           `json:"nonce"`
+	GasLimit             uint64           `json:"gasLimit"`
+	GasPrice             string           `json:"gasPrice,omitempty"`
+	MaxFeePerGas         string           `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string           `json:"maxPriorityFeePerGas,omitempty"`
+	To                   *string          `json:"to"`
+	ContractAddress      string           `json:"contractAddress,omitempty"`
+	Value                string           `json:"value"`
+	Data                 string           `json:"data"`
+	AccessList           types.AccessList `json:"accessList,omitempty"`
+	Method               string           `json:"method,omitempty"`
+	Args                 []string         `json:"args,omitempty"`
+}
+
+// newTxOutput returns the signed transaction's fields, in wei, decoding the call data when either the ABI,
+// or the method signature, is known
+func newTxOutput(t *types.Transaction, raw []byte, method string, a *abi.ABI) (*txOutput, error) {
+	from, err := txSender(t)
+	if err != nil {
+		return nil, err
+	}
+	o := &txOutput{
+		Raw:        fmt.Sprintf("0x%x", raw),
+		Hash:       t.Hash().Hex(),
+		From:       from.Hex(),
+		ChainID:    t.ChainId().String(),
+		Type:       t.Type(),
+		Nonce:      t.Nonce(),
+		GasLimit:   t.Gas(),
+		Value:      t.Value().String(),
+		Data:       fmt.Sprintf("0x%x", t.Data()),
+		AccessList: t.AccessList(),
+	}
+	if t.Type() == types.DynamicFeeTxType {
+		o.MaxFeePerGas = t.GasFeeCap().String()
+		o.MaxPriorityFeePerGas = t.GasTipCap().String()
+	} else {
+		o.GasPrice = t.GasPrice().String()
+	}
+	if t.To() != nil {
+		to := t.To().Hex()
+		o.To = &to
+	}
+	addr := contractAddress(t, from)
+	if addr != nil {
+		o.ContractAddress = addr.Hex()
+	}
+
+	// Decode call data, when we know how to
+	if addr != nil || len(t.Data()) == 0 || method == "" || (a == nil && !strings.Contains(method, "(")) {
+		return o, nil
+	}
+	sig, values, err := decodeCallData(t.Data(), method, a)
+	if err != nil {
+		return nil, err
+	}
+	o.Method = sig
+	o.Args = make([]string, len(values))
+	for i := range values {
+		o.Args[i] = formatValue(values[i])
+	}
+	return o, nil
+}
+
+// printJSON prints the value as indented JSON
+func printJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
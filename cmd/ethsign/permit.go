@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/juztin/ethsign/encoding"
+	"github.com/juztin/ethsign/parser"
+)
+
+var permitTypes = map[string][]encoding.TypedDataField{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+	"Permit": {
+		{Name: "owner", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	},
+}
+
+func validatePermitArgs() error {
+	if !tokenFlag.IsSet() {
+		return errors.New("Must specify the token address [--token]")
+	} else if !spenderFlag.IsSet() {
+		return errors.New("Must specify the spender address [--spender]")
+	} else if *amountFlag == "" {
+		return errors.New("Must specify the amount to permit [--amount]")
+	} else if valueFlag.IsSet() {
+		return errors.New("Permits don't send ether, specify the amount to permit with --amount")
+	} else if !flagIsSet("deadline") {
+		return errors.New("Must specify the permit deadline [--deadline]")
+	} else if !flagIsSet("nonce") {
+		return errors.New("Must specify the signing key's permit nonce of the token [--nonce]")
+
+	} else if *nameFlag == "" {
+		return errors.New("Must specify the token's EIP-712 domain name [--name]")
+	} else if len(args) > 0 {
+		return errors.New("Unexpected arguments, permits are given by flags")
+	}
+	return nil
+}
+
+// permitValue returns --amount in token base units, with `max` being the max uint256
+func permitValue() (*big.Int, error) {
+	if strings.ToLower(*amountFlag) == "max" {
+		max := new(big.Int).Lsh(big.NewInt(1), 256)
+		return max.Sub(max, big.NewInt(1)), nil
+	}
+	v, err := parser.ParseNumber(*amountFlag, 0)
+	if err != nil {
+		return nil, err
+	} else if v.Sign() < 0 || v.BitLen() > 256 {
+		return nil, fmt.Errorf("Invalid permit amount '%s'", *amountFlag)
+	}
+	return v, nil
+}
+
+// signPermit signs, and prints, the EIP-2612 permit signature components, and optionally the `permit` call data
+func signPermit(w io.Writer) error {
+	value, err := permitValue()
+	if err != nil {
+		return err
+	}
+	s, err := loadSigner()
+	if err != nil {
+		return err
+	}
+	owner := s.Address()
+
+	d := &encoding.TypedData{
+		Types:       permitTypes,
+		PrimaryType: "Permit",
+		Domain: map[string]interface{}{
+			"name":              *nameFlag,
+			"version":           *versionFlag,
+			"chainId":           chainFlag.String(),
+			"verifyingContract": tokenFlag.Value.Hex(),
+		},
+		Message: map[string]interface{}{
+			"owner":    owner.Hex(),
+			"spender":  spenderFlag.Value.Hex(),
+			"value":    value.String(),
+			"nonce":    fmt.Sprint(*nonceFlag),
+			"deadline": deadlineFlag.String(),
+		},
+	}
+	digest, err := d.Digest()
+	if err != nil {
+		return err
+	}
+	sig, err := s.SignHash(digest)
+	if err != nil {
+		return err
+	}
+	v, r, ss := sig[64]+27, sig[:32], sig[32:64]
+	fmt.Fprintf(w, "owner:    %s\n", owner.Hex())
+	fmt.Fprintf(w, "v:        %d\n", v)
+	fmt.Fprintf(w, "r:        0x%x\n", r)
+	fmt.Fprintf(w, "s:        0x%x\n", ss)
+	if !*calldataFlag {
+		return nil
+	}
+
+	data, err := parser.ParseMethod("permit(address,address,uint256,uint256,uint8,bytes32,bytes32)", []string{
+		owner.Hex(),
+		spenderFlag.Value.Hex(),
+		value.String(),
+		deadlineFlag.String(),
+		fmt.Sprint(v),
+		fmt.Sprintf("0x%x", r),
+		fmt.Sprintf("0x%x", ss),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "calldata: 0x%x\n", data)
+	return nil
+}
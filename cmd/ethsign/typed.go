@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juztin/ethsign/encoding"
+)
+
+func validateTypedArgs() error {
+	if err := validateV(); err != nil {
+		return err
+	}
+	if dataFlag.String() == "" {
+		return errors.New("Must specify the typed data file [--data]")
+	} else if len(args) > 0 {
+		return errors.New("Unexpected arguments, typed data is read from --data")
+	}
+	return nil
+}
+
+// signTyped prints the domain separator, struct hash and digest, for review, to stderr, before signing,
+// and printing, the 65-byte [R || S || V] EIP-712 signature
+func signTyped(w io.Writer) error {
+	d, err := encoding.ReadTypedData(dataFlag.String())
+	if err != nil {
+		return err
+	}
+	domain, err := d.DomainSeparator()
+	if err != nil {
+		return fmt.Errorf("Invalid domain: %w", err)
+	}
+	msg, err := d.HashStruct(d.PrimaryType, d.Message)
+	if err != nil {
+		return fmt.Errorf("Invalid message: %w", err)
+	}
+	digest, err := d.Digest()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Primary Type:     %s\n", d.PrimaryType)
+	fmt.Fprintf(os.Stderr, "Domain Separator: 0x%x\n", domain)
+	fmt.Fprintf(os.Stderr, "Struct Hash:      0x%x\n", msg)
+	fmt.Fprintf(os.Stderr, "Digest:           0x%x\n", digest)
+
+	s, err := loadSigner()
+	if err != nil {
+		return err
+	}
+	sig, err := s.SignHash(digest)
+	if err != nil {
+		return err
+	}
+	sig[64] += byte(*vFlag)
+	fmt.Fprintf(w, "0x%x", sig)
+	return nil
+}
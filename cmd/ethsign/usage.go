@@ -1,12 +1,53 @@
 package main
 
 const USAGE = `
+COMMANDS
+  ether       Sign a transaction sending ether, with an optional message.
+  call        Sign a contract function call, by signature or ABI function name.
+  deploy      Sign a contract deployment, printing the predicted contract address to stderr.
+  batch       Sign every transaction of a CSV, or JSON, --manifest, with consecutive nonces, one raw
+              transaction per line. Every row is checked before anything is signed.
+  decode      Decode, and print, a raw signed transaction.
+  address     Print the signing key's address, or list HD wallet addresses.
+  sign-message
+              Sign an EIP-191 (personal_sign) message, printing the 65-byte r||s||v signature.
+  permit      Sign an EIP-2612 ERC-20 permit, printing v, r, s and optionally the permit call data.
+  sign-typed  Sign EIP-712 typed data (eth_signTypedData_v4 JSON), printing the 65-byte r||s||v signature.
+  verify      Recover, and print, the signer of a raw transaction, message or typed data.
+              Exits non-zero when the signer isn't --expect.
+  abi         Print the --abi, or --artifact, functions, constructor, events and errors, with their signatures,
+              selectors, topics, state mutability and typed inputs and outputs. Optionally only those matching
+              a name, signature or selector, or, without an ABI, the given function signature.
+  nonce       Show, set or reset the offline nonce ledger [show, set <nonce>, reset].
+              Without a key, show lists every address and chain within the ledger.
+
 ARGUMENTS
   --abi f͟i͟l͟e͟
           Contract Application Binary Interface file.
+          Functions are given by name, canonical signature, eg. "safeTransferFrom(address,address,uint256,bytes)",
+          or 4-byte selector, eg. 0xb88d4fde. Overloaded functions are chosen by their number of arguments.
+          For deploy, the arguments are checked against the constructor, which must be payable to send --value.
+
+  --account-index n͟
+          The HD wallet account index, replacing the last component of --hd-path, up to 2147483647.
+
+  --accessList f͟i͟l͟e͟
+          EIP-2930 access list JSON file, in the form returned by eth_createAccessList.
+          Creates an access list (type 1) transaction, or is included within a dynamic fee (type 2)
+          transaction when --maxFeePerGas is given.
+
+  --amount n͟
+          The amount to permit, in token base units, or 'max' for the max uint256, for permit.
+
+  --artifact f͟i͟l͟e͟
+          Contract artifact file, providing the ABI, creation bytecode and linked libraries, in place of --abi and --bin.
+          Either a Hardhat artifact, a Foundry out/*.json artifact, or solc --combined-json abi,bin output.
 
   --bin f͟i͟l͟e͟
-          Contract compiled bytecode file.
+          Contract compiled, hex-encoded, bytecode file.
+
+  --calldata
+          Print the complete permit(owner,spender,value,deadline,v,r,s) call data, for permit.
 
   --chain n͟
           The Ethereum chain, for EIP-155 signing.
@@ -19,6 +60,31 @@ ARGUMENTS
          1337 - Geth private chain
       [default 1337]
 
+  --contract n͟a͟m͟e͟
+          The contract name, or fully qualified name, within a solc --combined-json --artifact.
+          Required when the file contains more than one contract.
+
+  --create2
+          Deploy the contract with CREATE2, as a call to the --factory with the --salt and init code.
+
+  --count n͟
+          The number of HD wallet addresses to list, for the address command.
+      [DEFAULT 5]
+
+  --deadline n͟
+          The permit deadline, as a unix timestamp, for permit.
+
+  --data f͟i͟l͟e͟
+          EIP-712 typed data JSON file (types, primaryType, domain, message), for sign-typed.
+          The domain separator, struct hash and digest are printed for review, before signing.
+
+  --expect a͟d͟d͟r͟e͟s͟s͟
+          The expected signer, for verify.
+
+  --factory a͟d͟d͟r͟e͟s͟s͟
+          The CREATE2 deployer factory, which deploys the init code following the 32-byte salt of the call data.
+      [DEFAULT 0x4e59b44847b379578588920ca78fbf26c0b4956c]
+
   --gasPrice n͟
           The gas price in Gwei.
       [DEFAULT 1]
@@ -27,37 +93,199 @@ ARGUMENTS
           The maximum amount of gas the transaction may consume.
       [DEFAULT 100000]
 
+  --hd-path p͟a͟t͟h͟
+          The BIP-32 HD wallet derivation path, for --mnemonic-file.
+      [DEFAULT m/44'/60'/0'/0/0]
+
+  --hex
+          The sign-message message, or --message-file contents, is hex-encoded bytes.
+
   --key f͟i͟l͟e͟
-          File containing either the raw private key, or a Go-Ethereum keystore file.
-          A password prompt will occur for keystore files.
-      [REQUIRED]
+          File containing the raw, hex-encoded, private key.
+
+  --key-env n͟a͟m͟e͟
+          Environment variable containing the raw, hex-encoded, private key.
+
+  --keystore f͟i͟l͟e͟
+          Go-Ethereum encrypted keystore file. A passphrase prompt will occur.
+
+  --message-file f͟i͟l͟e͟
+          File containing the message to sign, for sign-message.
+
+  --mnemonic-file f͟i͟l͟e͟
+          File containing a BIP-39 mnemonic, deriving the key at --hd-path.
+
+      One of --key, --keystore, --key-env or --mnemonic-file is [REQUIRED]
+
+  --ledger
+          Use the next nonce of the signing key's address on --chain from the offline nonce ledger,
+          and record it once signed. An explicit --nonce is used as-is, and is also recorded.
+      [DEFAULT false]
+
+  --ledger-file f͟i͟l͟e͟
+          The offline nonce ledger file.
+      [DEFAULT ~/.ethsign/nonces.json]
+
+  --libraries f͟i͟l͟e͟
+          Library addresses JSON file, for deploy, keyed by name, eg. {"Math": "0x.."}, or by source file
+          and name as in solc standard JSON, eg. {"contracts/Math.sol": {"Math": "0x.."}}.
+
+  --link n͟a͟m͟e͟=a͟d͟d͟r͟e͟s͟s͟
+          Library address, for deploy, replacing the bytecode's library placeholders. Repeatable.
+          The name is either the library name, eg. Math, its fully qualified name, eg. contracts/Math.sol:Math,
+          or the 34 hex character hash of a __$..$__ placeholder. Unresolved placeholders, and library names
+          shared by more than one of the bytecode's libraries, are an error.
+
+  --manifest f͟i͟l͟e͟
+          CSV, or JSON (.json), manifest of transactions, for batch.
+          CSV manifests have a header naming the columns, the arg column repeating once per argument.
+            kind,to,value,method,arg,arg,gasLimit,gasPrice,maxFeePerGas,maxPriorityFeePerGas
+          JSON manifests are an array of objects, with the same fields, and args as an array.
+          The kind is either ether, or call (by function signature, or --abi function name).
+          Values are in Ether, and fees in Gwei, with empty values using the flags.
+
+  --maxFeePerGas n͟
+          The EIP-1559 maximum fee per gas in Gwei.
+          When given, along with --maxPriorityFeePerGas, a dynamic fee (type 2) transaction is created.
+
+  --maxPriorityFeePerGas n͟
+          The EIP-1559 maximum priority fee (tip) per gas in Gwei.
+
+  --mnemonic-passphrase
+          Prompt for the optional BIP-39 mnemonic passphrase.
+
+  --name n͟a͟m͟e͟
+          The token's EIP-712 domain name, for permit.
 
   --nonce n͟
-          The next nonce of the address for the --key file.
+          The next nonce of the address of the signing key.
+          For permit, the signing key's next permit nonce of the token, which is [REQUIRED].
       [DEFAULT 0]
 
+  --output f͟o͟r͟m͟a͟t͟
+          The signed transaction output, either the raw hex transaction, or json.
+          JSON includes the raw transaction, hash, sender, chain, type, nonce, gas, fees, recipient,
+          value (in wei), data, and the method and arguments when the call data can be decoded,
+          or for deploy, the predicted contract address.
+          For batch, json is an array of each transaction, along with its manifest line.
+          For abi, json is an array of each function, constructor, event and error.
+      [DEFAULT hex]
+
+  --padBytes
+          Right-pad fixed-size bytes (bytes1..bytes32) arguments, shorter than their size, with zeros.
+          Otherwise the value must match the size exactly.
+      [DEFAULT false]
+
+  --salt h͟e͟x͟
+          The CREATE2 salt, as exactly 32 hex-encoded bytes.
+
+  --signature h͟e͟x͟
+          The 65-byte r||s||v signature of the message, or --data typed data, for verify.
+
+  --spender a͟d͟d͟r͟e͟s͟s͟
+          The address permitted to spend the tokens, for permit.
+
   --to a͟d͟d͟r͟e͟s͟s͟
           The recipient of either the ether, the contract address of the invocation, or both.
       Not required when signing a transaction for contract deployment.
 
+  --token a͟d͟d͟r͟e͟s͟s͟
+          The ERC-20 token address, the EIP-712 verifying contract, for permit.
+
+  --v n͟
+          The signature V convention, for sign-message and sign-typed, either 27 (27/28) or 0 (0/1).
+      [DEFAULT 27]
+
   --value n͟
           The amount in Ether to send with the transaction.
       [DEFAULT 0]
 
+  --version v͟e͟r͟s͟i͟o͟n͟
+          The token's EIP-712 domain version, for permit.
+      [DEFAULT 1]
+
+NUMBERS
+  Integer arguments, and the --amount, --chain, --gasPrice, --maxFeePerGas, --maxPriorityFeePerGas and --value
+  flags, accept hex, digit separators, exponents and unit suffixes (wei, gwei, ether, ...).
+    42  0xff  1_000_000  1e18  1.5ether  250gwei
+
 EXAMPLES
 
   Sending ether:
-    ethsign ether --to 0x1111111111111111111111111111111111111111 --key keyfile.json --value 0.05
+    ethsign ether --to 0x1111111111111111111111111111111111111111 --keystore keyfile.json --value 0.05
 
   Transfer ERC-20 tokens:
-    ethsign call "transfer(address,uint256)" 0xffffffffffffffffffffffffffffffffffffffff 42 --key keyfile.json
+    ethsign call "transfer(address,uint256)" 0xffffffffffffffffffffffffffffffffffffffff 42 --keystore keyfile.json
+
+  Function call with a tuple (struct) argument, positionally, or as JSON keyed by the ABI's component names
+    ethsign call "f((address,uint256),bool)" "(0xffffffffffffffffffffffffffffffffffffffff,42)" true --keystore keyfile.json
+    ethsign call exactInputSingle '{"tokenIn":"0x11..11","tokenOut":"0x22..22","fee":3000,...}' --abi router.abi --keystore keyfile.json
 
-  Function call from contract ABI
+  Function call from contract ABI, by name, or an overloaded function by signature or selector
     ethsign call funcName arg1 arg2 --to 0x1111111111111111111111111111111111111111 --abi contract.abi --key keyfile.txt
+    ethsign call "safeTransferFrom(address,address,uint256,bytes)" 0x11..11 0x22..22 42 0x --to 0x1111111111111111111111111111111111111111 --abi erc721.abi --key keyfile.txt
+    ethsign call 0xb88d4fde 0x11..11 0x22..22 42 0x --to 0x1111111111111111111111111111111111111111 --abi erc721.abi --key keyfile.txt
+
+  Sending ether, using EIP-1559 fees:
+    ethsign ether --to 0x1111111111111111111111111111111111111111 --keystore keyfile.json --value 0.05 --chain 1 --maxFeePerGas 30 --maxPriorityFeePerGas 1.5
+
+  Contract deployment, with constructor arguments. With an ABI every argument is a constructor argument.
+    ethsign deploy arg1 arg2 --abi contract.abi --bin contract.bin --keystore keyfile.json
+    ethsign deploy constructor(string,uint256) arg1 arg2 --bin contract.bin --keystore keyfile.json
+
+  Contract deployment, and function calls, from a Hardhat, Foundry or solc combined-json artifact
+    ethsign deploy arg1 arg2 --artifact artifacts/contracts/Token.sol/Token.json --keystore keyfile.json
+    ethsign deploy arg1 arg2 --artifact combined.json --contract Token --link Math=0x1111111111111111111111111111111111111111 --keystore keyfile.json
+    ethsign call funcName arg1 --to 0x1111111111111111111111111111111111111111 --artifact out/Token.sol/Token.json --keystore keyfile.json
+
+  Contract deployment, with CREATE2, to the same address across chains
+    ethsign deploy arg1 arg2 --abi contract.abi --bin contract.bin --create2 --salt 0x0000000000000000000000000000000000000000000000000000000000000001 --chain 1 --keystore keyfile.json
+
+  Contract deployment, linking libraries
+    ethsign deploy --bin contract.bin --link Math=0x1111111111111111111111111111111111111111 --keystore keyfile.json
+    ethsign deploy --bin contract.bin --libraries libraries.json --keystore keyfile.json
+
+  Listing HD wallet addresses, then signing with the second
+    ethsign address --mnemonic-file seed.txt --count 10
+    ethsign ether --to 0x1111111111111111111111111111111111111111 --value 0.05 --mnemonic-file seed.txt --account-index 1
+
+  Signing a message
+    ethsign sign-message "I own this address" --keystore keyfile.json
+    ethsign sign-message 0xdeadbeef --hex --keystore keyfile.json --v 0
+    ethsign sign-message --message-file siwe.txt --keystore keyfile.json
+
+  Signing EIP-712 typed data
+    ethsign sign-typed --data permit.json --keystore keyfile.json
+
+  Signing an ERC-20 permit, with the permit call data
+    ethsign permit --token 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 --spender 0x1111111111111111111111111111111111111111 \
+      --amount 1000000 --deadline 1800000000 --nonce 0 --name "USD Coin" --version 2 --chain 1 --calldata --keystore keyfile.json
+
+  Verifying signers
+    ethsign verify 0xf869... --expect 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23
+    ethsign verify "I own this address" --signature 0x4355... --expect 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23
+    ethsign verify --data permit.json --signature 0x4355...
+
+  Signing consecutive transactions, tracking nonces offline, then correcting the ledger
+    ethsign ether --to 0x1111111111111111111111111111111111111111 --value 0.05 --chain 1 --ledger --keystore keyfile.json
+    ethsign nonce set 42 --chain 1 --keystore keyfile.json
+    ethsign nonce show
+
+  Signing a transaction, printing the hash, sender and decoded call as JSON
+    ethsign call "transfer(address,uint256)" 0xffffffffffffffffffffffffffffffffffffffff 42 --to 0x1111111111111111111111111111111111111111 --output json --keystore keyfile.json
+
+  Signing a manifest of transactions, starting at nonce 42
+    ethsign batch --manifest payroll.csv --chain 1 --maxFeePerGas 30 --maxPriorityFeePerGas 1.5 --nonce 42 --keystore keyfile.json
+
+  Inspecting an ABI's functions, events and errors, an overloaded function, or a function signature
+    ethsign abi --abi contract.abi
+    ethsign abi safeTransferFrom --abi erc721.abi --output json
+    ethsign abi "transfer(address,uint)"
 
-  Contract deployment, with constructor arguments
-    ethsign deploy arg1 arg2 --abi contract.abi --bin contract.bin --key keyfile.json
-    ethsign deploy constructor(string,uint256) arg1 arg2 --bin contract.bin --key keyfile.json
+  Decoding a raw transaction, with optional call data decoding by signature or ABI
+    ethsign decode 0xf869...
+    ethsign decode 0xf8a9... "transfer(address,uint256)"
+    ethsign decode 0xf8a9... --abi contract.abi
 
   Generating a QR-Code (using 'qr-code' tool: 'go get github.com/juztin/qr-code')
     qrcode echo "https://etherscan.io/pushTx?hex=0x$(ethsign ether --to 0xffffffffffffffffffffffffffffffffffffffff --value 0.25 --key keyfile.key --nonce 42 -gasPrice 2 -gasLimit 21000)" > transaction.png
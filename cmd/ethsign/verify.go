@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/juztin/ethsign/encoding"
+	"github.com/juztin/ethsign/signer"
+)
+
+func validateVerifyArgs() error {
+	switch {
+	case dataFlag.String() != "":
+		if *signatureFlag == "" {
+			return errors.New("Must specify the typed data signature [--signature]")
+		} else if len(args) > 0 {
+			return errors.New("Unexpected arguments, typed data is read from --data")
+		}
+	case *signatureFlag != "":
+		if messageFlag.String() == "" && len(args) != 1 {
+			return errors.New("Must specify a single message, or message file [--message-file]")
+		} else if messageFlag.String() != "" && len(args) > 0 {
+			return errors.New("Can't specify both a message, and message file [--message-file]")
+		}
+	case len(args) != 1:
+		return errors.New("Must specify a raw transaction, or a message or typed data with a signature [--signature]")
+	}
+	return nil
+}
+
+// recoverSigner returns the address which signed the raw transaction, typed data or message
+func recoverSigner() (common.Address, error) {
+	if *signatureFlag == "" {
+		t, err := decodeRawTx(args[0])
+		if err != nil {
+			return common.Address{}, err
+		}
+		return txSender(t)
+	}
+
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(*signatureFlag), "0x"))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("Invalid signature: %w", err)
+	}
+	if dataFlag.String() != "" {
+		d, err := encoding.ReadTypedData(dataFlag.String())
+		if err != nil {
+			return common.Address{}, err
+		}
+		digest, err := d.Digest()
+		if err != nil {
+			return common.Address{}, err
+		}
+		return signer.RecoverHash(digest, sig)
+	}
+	msg, err := readMessage()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return signer.RecoverMessage(msg, sig)
+}
+
+// verify prints the recovered signer, failing when it isn't the --expect address
+func verify(w io.Writer) error {
+	addr, err := recoverSigner()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, addr.Hex())
+	if expectFlag.IsSet() && addr != expectFlag.Value {
+		return fmt.Errorf("Signer %s doesn't match expected %s", addr.Hex(), expectFlag.Value.Hex())
+	}
+	return nil
+}
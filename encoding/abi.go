@@ -15,9 +15,9 @@ func DecodeArgs(args abi.Arguments, s ...string) ([]interface{}, error) {
 	var err error
 	packed := make([]interface{}, len(s), len(s))
 	for i := range args {
-		packed[i], err = parser.ParseValue(args[i].Type.String(), s[i])
+		packed[i], err = parser.ParseArgument(args[i].Type, s[i])
 		if err != nil {
-			break
+			return nil, &parser.ArgumentError{Index: i, Type: args[i].Type.String(), Err: err}
 		}
 	}
 	return packed, err
@@ -0,0 +1,89 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type accessTuple struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+// ReadAccessList reads and decodes the access list within the given file
+func ReadAccessList(path string) (types.AccessList, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeAccessList(b)
+}
+
+// DecodeAccessList decodes either an `eth_createAccessList` result, or a bare access list array, validating addresses and storage keys
+func DecodeAccessList(b []byte) (types.AccessList, error) {
+	var tuples []accessTuple
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '{' {
+		var result struct {
+			AccessList *[]accessTuple `json:"accessList"`
+		}
+		if err := json.Unmarshal(b, &result); err != nil {
+			return nil, fmt.Errorf("Invalid access list: %w", err)
+		} else if result.AccessList == nil {
+			return nil, fmt.Errorf("Invalid access list: missing 'accessList'")
+		}
+		tuples = *result.AccessList
+	} else if err := json.Unmarshal(b, &tuples); err != nil {
+		return nil, fmt.Errorf("Invalid access list: %w", err)
+	}
+
+	list := make(types.AccessList, len(tuples))
+	for i, t := range tuples {
+		addr, err := decodeChecksumAddress(t.Address)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid access list entry %d: %w", i, err)
+		}
+		keys := make([]common.Hash, len(t.StorageKeys))
+		for j, k := range t.StorageKeys {
+			keys[j], err = decodeStorageKey(k)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid access list entry %d, storage key %d: %w", i, j, err)
+			}
+		}
+		list[i] = types.AccessTuple{Address: addr, StorageKeys: keys}
+	}
+	return list, nil
+}
+
+// decodeChecksumAddress decodes the address, ensuring mixed-case addresses have a valid EIP-55 checksum
+func decodeChecksumAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid address '%s'", s)
+	}
+	a := common.HexToAddress(s)
+	h := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if h != strings.ToLower(h) && h != strings.ToUpper(h) && a.Hex()[2:] != h {
+		return a, fmt.Errorf("invalid address checksum '%s'", s)
+	}
+	return a, nil
+}
+
+func decodeStorageKey(s string) (common.Hash, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return common.Hash{}, fmt.Errorf("storage key must be 0x-prefixed '%s'", s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("invalid storage key '%s': %w", s, err)
+	} else if len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid storage key length, expected %d got %d", common.HashLength, len(b))
+	}
+	return common.BytesToHash(b), nil
+}
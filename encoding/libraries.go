@@ -0,0 +1,50 @@
+package encoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ReadLibraries reads and decodes the library addresses within the given file
+func ReadLibraries(path string) (map[string]common.Address, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeLibraries(b)
+}
+
+// DecodeLibraries decodes library addresses, either keyed by name, eg. `{"Math": "0x.."}`, or by source file
+// and name as in solc's standard JSON settings, eg. `{"contracts/Math.sol": {"Math": "0x.."}}`, which are
+// keyed by their fully qualified name `contracts/Math.sol:Math`
+func DecodeLibraries(b []byte) (map[string]common.Address, error) {
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("Invalid libraries: %w", err)
+	}
+
+	libs := make(map[string]common.Address)
+	for name, raw := range entries {
+		var addr string
+		if err := json.Unmarshal(raw, &addr); err == nil {
+			if libs[name], err = decodeChecksumAddress(addr); err != nil {
+				return nil, fmt.Errorf("Invalid library '%s': %w", name, err)
+			}
+			continue
+		}
+		var file map[string]string
+		if err := json.Unmarshal(raw, &file); err != nil {
+			return nil, fmt.Errorf("Invalid library '%s', expected an address, or libraries of the file", name)
+		}
+		for lib, addr := range file {
+			var err error
+			if libs[name+":"+lib], err = decodeChecksumAddress(addr); err != nil {
+				return nil, fmt.Errorf("Invalid library '%s:%s': %w", name, lib, err)
+			}
+		}
+	}
+	return libs, nil
+}
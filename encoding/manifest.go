@@ -0,0 +1,178 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// ManifestRow is a single transaction of a batch manifest, with each value kept as its literal string
+type ManifestRow struct {
+	Line                 int
+	Kind                 string
+	To                   string
+	Value                string
+	Method               string
+	Args                 []string
+	GasLimit             string
+	GasPrice             string
+	MaxFeePerGas         string
+	MaxPriorityFeePerGas string
+}
+
+// manifestText is a JSON manifest value, either a string or the literal JSON (numbers, tuple objects and arrays)
+type manifestText string
+
+func (t *manifestText) UnmarshalJSON(b []byte) error {
+	var s string
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	} else if !bytes.Equal(b, []byte("null")) {
+		s = string(b)
+	}
+	*t = manifestText(s)
+	return nil
+}
+
+type manifestObject struct {
+	Kind                 manifestText   `json:"kind"`
+	To                   manifestText   `json:"to"`
+	Value                manifestText   `json:"value"`
+	Method               manifestText   `json:"method"`
+	Args                 []manifestText `json:"args"`
+	GasLimit             manifestText   `json:"gasLimit"`
+	GasPrice             manifestText   `json:"gasPrice"`
+	MaxFeePerGas         manifestText   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas manifestText   `json:"maxPriorityFeePerGas"`
+}
+
+// ReadManifest reads, and decodes, the batch manifest file, as JSON when it has a `.json` extension, otherwise CSV
+func ReadManifest(path string) ([]ManifestRow, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return DecodeJSONManifest(b)
+	}
+	return DecodeCSVManifest(b)
+}
+
+// DecodeJSONManifest decodes an array of manifest objects, eg.
+// `[{"kind": "call", "to": "0x..", "method": "transfer(address,uint256)", "args": ["0x..", 42]}]`
+func DecodeJSONManifest(b []byte) ([]ManifestRow, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("Invalid manifest: %w", err)
+	}
+
+	rows := make([]ManifestRow, len(raw))
+	offset := 0
+	for i, r := range raw {
+		// Elements are in order, so each is found after the previous, giving its line
+		line := bytes.Count(b[:offset], []byte("\n")) + 1
+		if n := bytes.Index(b[offset:], r); n >= 0 {
+			line += bytes.Count(b[offset:offset+n], []byte("\n"))
+			offset += n + len(r)
+		}
+
+		var o manifestObject
+		dec := json.NewDecoder(bytes.NewReader(r))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&o); err != nil {
+			return nil, fmt.Errorf("Line %d: Invalid manifest row: %w", line, err)
+		}
+		rows[i] = ManifestRow{
+			Line:                 line,
+			Kind:                 string(o.Kind),
+			To:                   string(o.To),
+			Value:                string(o.Value),
+			Method:               string(o.Method),
+			GasLimit:             string(o.GasLimit),
+			GasPrice:             string(o.GasPrice),
+			MaxFeePerGas:         string(o.MaxFeePerGas),
+			MaxPriorityFeePerGas: string(o.MaxPriorityFeePerGas),
+		}
+		for _, a := range o.Args {
+			rows[i].Args = append(rows[i].Args, string(a))
+		}
+	}
+	return rows, nil
+}
+
+// DecodeCSVManifest decodes a CSV manifest, one row per line, with a header naming the columns
+// [kind, to, value, method, arg, gasLimit, gasPrice, maxFeePerGas, maxPriorityFeePerGas].
+// The arg column repeats, once per argument, and empty trailing arguments are ignored.
+// Blank lines, and lines starting with '#', are skipped.
+func DecodeCSVManifest(b []byte) ([]ManifestRow, error) {
+	var header []string
+	var rows []ManifestRow
+	for i, l := range strings.Split(string(b), "\n") {
+		line := i + 1
+		l = strings.TrimSpace(l)
+		if l == "" || l[0] == '#' {
+			continue
+		}
+		fields, err := csv.NewReader(strings.NewReader(l)).Read()
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: Invalid manifest row: %w", line, err)
+		}
+
+		if header == nil {
+			for _, h := range fields {
+				h = strings.TrimSpace(h)
+				switch strings.ToLower(h) {
+				case "kind", "to", "value", "method", "arg", "gaslimit", "gasprice", "maxfeepergas", "maxpriorityfeepergas":
+				default:
+					return nil, fmt.Errorf("Line %d: Invalid manifest column '%s'", line, h)
+				}
+				header = append(header, strings.ToLower(h))
+			}
+			continue
+		} else if len(fields) != len(header) {
+			return nil, fmt.Errorf("Line %d: Expected %d columns, got %d", line, len(header), len(fields))
+		}
+
+		r := ManifestRow{Line: line}
+		args := 0
+		for j, f := range fields {
+			f = strings.TrimSpace(f)
+			switch header[j] {
+			case "kind":
+				r.Kind = f
+			case "to":
+				r.To = f
+			case "value":
+				r.Value = f
+			case "method":
+				r.Method = f
+			case "arg":
+				r.Args = append(r.Args, f)
+				if f != "" {
+					args = len(r.Args)
+				}
+			case "gaslimit":
+				r.GasLimit = f
+			case "gasprice":
+				r.GasPrice = f
+			case "maxfeepergas":
+				r.MaxFeePerGas = f
+			case "maxpriorityfeepergas":
+				r.MaxPriorityFeePerGas = f
+			}
+		}
+		r.Args = r.Args[:args]
+		rows = append(rows, r)
+	}
+	if header == nil {
+		return nil, errors.New("Invalid manifest: missing header")
+	}
+	return rows, nil
+}
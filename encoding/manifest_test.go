@@ -0,0 +1,56 @@
+package encoding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSONManifestLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		lines []int
+	}{
+		{
+			name:  "single line",
+			json:  `[{"to": "0x01"}, {"to": "0x02"}]`,
+			lines: []int{1, 1},
+		},
+		{
+			name:  "one row per line",
+			json:  "[\n  {\"to\": \"0x01\"},\n  {\"to\": \"0x02\"},\n  {\"to\": \"0x03\"}\n]",
+			lines: []int{2, 3, 4},
+		},
+		{
+			name:  "identical rows",
+			json:  "[\n  {\"to\": \"0x01\"},\n  {\"to\": \"0x01\"},\n\n  {\"to\": \"0x01\"}\n]",
+			lines: []int{2, 3, 5},
+		},
+		{
+			name:  "multi-line rows",
+			json:  "[\n  {\n    \"to\": \"0x01\"\n  },\n  {\n    \"to\": \"0x01\"\n  }\n]",
+			lines: []int{2, 5},
+		},
+	}
+	for _, tt := range tests {
+		rows, err := DecodeJSONManifest([]byte(tt.json))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		} else if len(rows) != len(tt.lines) {
+			t.Errorf("%s: expected %d rows, got %d", tt.name, len(tt.lines), len(rows))
+			continue
+		}
+		for i, r := range rows {
+			if r.Line != tt.lines[i] {
+				t.Errorf("%s: expected row %d on line %d, got %d", tt.name, i, tt.lines[i], r.Line)
+			}
+		}
+	}
+
+	// Errors report the line of the invalid row
+	_, err := DecodeJSONManifest([]byte("[\n  {\"to\": \"0x01\"},\n  {\"to\": \"0x01\"},\n  {\"bad\": 1}\n]"))
+	if err == nil || !strings.HasPrefix(err.Error(), "Line 4:") {
+		t.Errorf("expected an error on line 4, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/juztin/ethsign/parser"
+)
+
+// TypedDataField is a named, and typed, field of an EIP-712 struct type
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is EIP-712 typed structured data, in the `eth_signTypedData_v4` JSON form
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// domainFields are the EIP712Domain fields, in order, used when the domain type isn't given
+var domainFields = []TypedDataField{
+	{"name", "string"},
+	{"version", "string"},
+	{"chainId", "uint256"},
+	{"verifyingContract", "address"},
+	{"salt", "bytes32"},
+}
+
+// ReadTypedData reads, and decodes, the EIP-712 typed data file
+func ReadTypedData(path string) (*TypedData, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeTypedData(b)
+}
+
+// DecodeTypedData decodes the EIP-712 typed data, keeping numbers as their literal strings
+func DecodeTypedData(b []byte) (*TypedData, error) {
+	d := new(TypedData)
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(d); err != nil {
+		return nil, fmt.Errorf("Invalid typed data: %w", err)
+	}
+	if d.PrimaryType == "" {
+		return nil, errors.New("Invalid typed data: missing 'primaryType'")
+	} else if _, ok := d.Types[d.PrimaryType]; !ok {
+		return nil, fmt.Errorf("Invalid typed data: missing type '%s'", d.PrimaryType)
+	}
+	if _, ok := d.Types["EIP712Domain"]; !ok {
+		if d.Types == nil {
+			d.Types = make(map[string][]TypedDataField)
+		}
+		for _, f := range domainFields {
+			if _, ok := d.Domain[f.Name]; ok {
+				d.Types["EIP712Domain"] = append(d.Types["EIP712Domain"], f)
+			}
+		}
+	}
+	return d, nil
+}
+
+// DomainSeparator returns the hash of the EIP712Domain struct
+func (d *TypedData) DomainSeparator() ([]byte, error) {
+	return d.HashStruct("EIP712Domain", d.Domain)
+}
+
+// Digest returns the EIP-712 digest, `keccak256(0x1901 || domainSeparator || hashStruct(message))`, to sign
+func (d *TypedData) Digest() ([]byte, error) {
+	domain, err := d.DomainSeparator()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid domain: %w", err)
+	}
+	msg, err := d.HashStruct(d.PrimaryType, d.Message)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid message: %w", err)
+	}
+	return crypto.Keccak256([]byte{0x19, 0x01}, domain, msg), nil
+}
+
+// EncodeType returns the encoded type, eg. `Mail(Person from,Person to,string contents)Person(string name,address wallet)`
+func (d *TypedData) EncodeType(primaryType string) string {
+	deps := d.dependencies(primaryType, map[string]bool{})
+	sort.Strings(deps)
+	var b strings.Builder
+	for _, t := range append([]string{primaryType}, deps...) {
+		fields := make([]string, len(d.Types[t]))
+		for i, f := range d.Types[t] {
+			fields[i] = f.Type + " " + f.Name
+		}
+		b.WriteString(t + "(" + strings.Join(fields, ",") + ")")
+	}
+	return b.String()
+}
+
+// TypeHash returns the hash of the encoded type
+func (d *TypedData) TypeHash(primaryType string) []byte {
+	return crypto.Keccak256([]byte(d.EncodeType(primaryType)))
+}
+
+// HashStruct returns `keccak256(typeHash || encodeData(data))` for the struct type
+func (d *TypedData) HashStruct(primaryType string, data map[string]interface{}) ([]byte, error) {
+	enc, err := d.encodeData(primaryType, data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(enc), nil
+}
+
+// dependencies returns the struct types referenced, directly or not, by the type, excluding itself
+func (d *TypedData) dependencies(t string, found map[string]bool) []string {
+	found[t] = true
+	var deps []string
+	for _, f := range d.Types[t] {
+		base := baseType(f.Type)
+		if _, ok := d.Types[base]; ok && !found[base] {
+			deps = append(deps, base)
+			deps = append(deps, d.dependencies(base, found)...)
+		}
+	}
+	return deps
+}
+
+func (d *TypedData) encodeData(t string, data map[string]interface{}) ([]byte, error) {
+	fields, ok := d.Types[t]
+	if !ok {
+		return nil, fmt.Errorf("missing type '%s'", t)
+	}
+	if len(data) > len(fields) {
+		for name := range data {
+			if !hasField(fields, name) {
+				return nil, fmt.Errorf("unknown field '%s' for type '%s'", name, t)
+			}
+		}
+	}
+	enc := d.TypeHash(t)
+	for _, f := range fields {
+		v, ok := data[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing field '%s' for type '%s'", f.Name, t)
+		}
+		b, err := d.encodeValue(f.Type, v)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", f.Name, err)
+		}
+		enc = append(enc, b...)
+	}
+	return enc, nil
+}
+
+// encodeValue returns the 32-byte encoding of the value
+func (d *TypedData) encodeValue(t string, v interface{}) ([]byte, error) {
+	// Arrays, the hash of their concatenated encoded elements
+	if strings.HasSuffix(t, "]") {
+		i := strings.LastIndex(t, "[")
+		elems, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an array for '%s'", t)
+		}
+		if size := t[i+1 : len(t)-1]; size != "" && size != fmt.Sprint(len(elems)) {
+			return nil, fmt.Errorf("invalid array length for '%s', got %d", t, len(elems))
+		}
+		var enc []byte
+		for _, e := range elems {
+			b, err := d.encodeValue(t[:i], e)
+			if err != nil {
+				return nil, err
+			}
+			enc = append(enc, b...)
+		}
+		return crypto.Keccak256(enc), nil
+	}
+
+	// Structs, their hash
+	if _, ok := d.Types[t]; ok {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an object for '%s'", t)
+		}
+		return d.HashStruct(t, m)
+	}
+
+	// Atomic, and dynamic, types are parsed the same as function arguments
+	s, err := valueString(v)
+	if err != nil {
+		return nil, err
+	}
+	switch t {
+	case "string":
+		return crypto.Keccak256([]byte(s)), nil
+	case "bytes":
+		b, err := parser.ParseValue(t, s)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b.([]byte)), nil
+	}
+	o, err := parser.ParseValue(t, s)
+	if err != nil {
+		return nil, err
+	}
+	abiType, err := abi.NewType(t, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return abi.Arguments{{Type: abiType}}.Pack(o)
+}
+
+// valueString returns the JSON value as the string parsed by parser.ParseValue
+func valueString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return fmt.Sprint(t), nil
+	}
+	return "", fmt.Errorf("unexpected value '%v'", v)
+}
+
+func baseType(t string) string {
+	if i := strings.Index(t, "["); i >= 0 {
+		return t[:i]
+	}
+	return t
+}
+
+func hasField(fields []TypedDataField, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package encoding
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// etherMail is the EIP-712 specification's example
+const etherMail = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+    "Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+  },
+  "primaryType": "Mail",
+  "domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+  "message": {
+    "from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+    "to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+    "contents": "Hello, Bob!"
+  }
+}`
+
+// etherMailArrays is the example with arrays of addresses, and of structs
+const etherMailArrays = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [{"name": "name", "type": "string"}, {"name": "wallets", "type": "address[]"}],
+    "Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person[]"}, {"name": "contents", "type": "string"}],
+    "Group": [{"name": "name", "type": "string"}, {"name": "members", "type": "Person[]"}]
+  },
+  "primaryType": "Mail",
+  "domain": {"name": "Ether Mail", "version": "1", "chainId": 1, "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+  "message": {
+    "from": {"name": "Cow", "wallets": ["0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", "0xDeaDbeefdEAdbeefdEadbEEFdeadbeEFdEaDbeeF"]},
+    "to": [{"name": "Bob", "wallets": [
+      "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB",
+      "0xB0BdaBea57B0BDABeA57b0bdABEA57b0BDabEa57",
+      "0xB0B0b0b0b0b0B000000000000000000000000000"
+    ]}],
+    "contents": "Hello, Bob!"
+  }
+}`
+
+// nested has dependencies out of order, atomic types, and an inferred domain type of name, chainId and salt
+const nested = `{
+  "types": {
+    "Ordered": [
+      {"name": "z", "type": "Zeta"},
+      {"name": "a", "type": "Alpha[]"},
+      {"name": "n", "type": "int8"},
+      {"name": "b", "type": "bytes"},
+      {"name": "ok", "type": "bool"},
+      {"name": "f", "type": "bytes4"}
+    ],
+    "Zeta": [{"name": "m", "type": "Mu"}],
+    "Mu": [{"name": "v", "type": "uint256"}],
+    "Alpha": [{"name": "v", "type": "uint16"}]
+  },
+  "primaryType": "Ordered",
+  "domain": {"salt": "0x0000000000000000000000000000000000000000000000000000000000000001", "chainId": 5, "name": "Test"},
+  "message": {"z": {"m": {"v": "0x10"}}, "a": [{"v": 1}, {"v": 2}], "n": -5, "b": "0xdeadbeef", "ok": true, "f": "0x01020304"}
+}`
+
+func TestTypedData(t *testing.T) {
+	tests := []struct {
+		name       string
+		json       string
+		encodeType string
+		domain     string
+		message    string
+		digest     string
+	}{
+		{
+			name:       "ether mail",
+			json:       etherMail,
+			encodeType: "Mail(Person from,Person to,string contents)Person(string name,address wallet)",
+			domain:     "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+			message:    "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e",
+			digest:     "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2",
+		},
+		{
+			name:       "ether mail arrays",
+			json:       etherMailArrays,
+			encodeType: "Mail(Person from,Person[] to,string contents)Person(string name,address[] wallets)",
+			domain:     "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+			message:    "eb4221181ff3f1a83ea7313993ca9218496e424604ba9492bb4052c03d5c3df8",
+			digest:     "a85c2e2b118698e88db68a8105b794a8cc7cec074e89ef991cb4f5f533819cc2",
+		},
+		{
+			name:       "nested, with an inferred domain",
+			json:       nested,
+			encodeType: "Ordered(Zeta z,Alpha[] a,int8 n,bytes b,bool ok,bytes4 f)Alpha(uint16 v)Mu(uint256 v)Zeta(Mu m)",
+			domain:     "3c7f83e6caa8c2adb2a5bb0613621d063a090714287d9f6ea9fbdbac1d6639e6",
+			message:    "0ff7d32bb14bd4f74ff7de725e9c83473d436840fcaf136712cb82a9c4d6de88",
+			digest:     "3d4745f1c310317dc2b0bd364f0b182913eba62d5377c06c8ae5127fd400716a",
+		},
+	}
+	for _, tt := range tests {
+		d, err := DecodeTypedData([]byte(tt.json))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got := d.EncodeType(d.PrimaryType); got != tt.encodeType {
+			t.Errorf("%s: expected encoded type %s, got %s", tt.name, tt.encodeType, got)
+		}
+		domain, err := d.DomainSeparator()
+		if err != nil {
+			t.Errorf("%s: unexpected domain error: %v", tt.name, err)
+		} else if got := hex.EncodeToString(domain); got != tt.domain {
+			t.Errorf("%s: expected domain separator %s, got %s", tt.name, tt.domain, got)
+		}
+		message, err := d.HashStruct(d.PrimaryType, d.Message)
+		if err != nil {
+			t.Errorf("%s: unexpected message error: %v", tt.name, err)
+		} else if got := hex.EncodeToString(message); got != tt.message {
+			t.Errorf("%s: expected message hash %s, got %s", tt.name, tt.message, got)
+		}
+		digest, err := d.Digest()
+		if err != nil {
+			t.Errorf("%s: unexpected digest error: %v", tt.name, err)
+		} else if got := hex.EncodeToString(digest); got != tt.digest {
+			t.Errorf("%s: expected digest %s, got %s", tt.name, tt.digest, got)
+		}
+	}
+}
+
+func TestTypedDataSignature(t *testing.T) {
+	// The specification's signer, the private key of keccak256("cow")
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := DecodeTypedData([]byte(etherMail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := d.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(digest, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := hex.EncodeToString(sig[:32]); r != "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" {
+		t.Errorf("unexpected r %s", r)
+	}
+	if s := hex.EncodeToString(sig[32:64]); s != "07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" {
+		t.Errorf("unexpected s %s", s)
+	}
+	if v := sig[64] + 27; v != 28 {
+		t.Errorf("unexpected v %d", v)
+	}
+}
+
+func TestTypedDataErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"missing primary type", `{"types": {"Mail": []}, "domain": {}, "message": {}}`},
+		{"undefined primary type", `{"types": {}, "primaryType": "Mail", "domain": {}, "message": {}}`},
+		{"missing field", `{"types": {"Mail": [{"name": "a", "type": "uint8"}]}, "primaryType": "Mail", "domain": {"name": "T"}, "message": {}}`},
+		{"unknown field", `{"types": {"Mail": []}, "primaryType": "Mail", "domain": {"name": "T"}, "message": {"a": 1}}`},
+		{"out of range", `{"types": {"Mail": [{"name": "a", "type": "uint8"}]}, "primaryType": "Mail", "domain": {"name": "T"}, "message": {"a": 256}}`},
+		{"fixed array length", `{"types": {"Mail": [{"name": "a", "type": "uint8[2]"}]}, "primaryType": "Mail", "domain": {"name": "T"}, "message": {"a": [1]}}`},
+		{"struct not an object", `{"types": {"Mail": [{"name": "p", "type": "P"}], "P": []}, "primaryType": "Mail", "domain": {"name": "T"}, "message": {"p": 1}}`},
+	}
+	for _, tt := range tests {
+		d, err := DecodeTypedData([]byte(tt.json))
+		if err == nil {
+			_, err = d.Digest()
+		}
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package flags
 
 import (
-	"errors"
 	"math/big"
+
+	"github.com/juztin/ethsign/parser"
 )
 
 type BigIntFlag struct {
@@ -14,9 +15,9 @@ func (f *BigIntFlag) String() string {
 }
 
 func (f *BigIntFlag) Set(value string) error {
-	i, ok := new(big.Int).SetString(value, 10)
-	if !ok {
-		return errors.New("Invalid number for big.Int")
+	i, err := parser.ParseNumber(value, 0)
+	if err != nil {
+		return err
 	}
 	f.i = i
 	return nil
@@ -1,11 +1,11 @@
 package flags
 
 import (
-	"errors"
 	"math/big"
-)
+	"strconv"
 
-var ether = big.NewFloat(1000000000000000000)
+	"github.com/juztin/ethsign/parser"
+)
 
 type Unit int64
 
@@ -23,10 +23,17 @@ const (
 	TETHER      = 1000000000000000000000000000000
 )
 
+// decimals returns the number of decimals of the unit, in wei
+func (u Unit) decimals() int {
+	return len(strconv.FormatInt(int64(u), 10)) - 1
+}
+
 // EtherFlag is a flag to convert from ether to wei
 type EtherFlag struct {
 	unit  Unit
 	value *big.Int
+	raw   string
+	isSet bool
 }
 
 // String returns the string value
@@ -34,17 +41,29 @@ func (f *EtherFlag) String() string {
 	return f.value.String()
 }
 
-// Set converts the given value from ether to wei, and sets it
+// Set converts the given value from the flag's unit to wei, and sets it.
+// Values may also be hex, or have their own unit suffix, eg. `1.5ether`, `250gwei`
 func (f *EtherFlag) Set(value string) error {
-	amount, ok := new(big.Float).SetString(value)
-	if !ok {
-		return errors.New("Invalid number for big.Float")
+	amount, err := parser.ParseNumber(value, f.unit.decimals())
+	if err != nil {
+		return err
 	}
-	u := new(big.Float).SetInt64(int64(f.unit))
-	amount.Mul(amount, u).Int(f.value)
+	f.value.Set(amount)
+	f.raw = value
+	f.isSet = true
 	return nil
 }
 
+// Raw returns the value as it was given, before conversion
+func (f *EtherFlag) Raw() string {
+	return f.raw
+}
+
+// IsSet returns whether the flag was explicitly set
+func (f *EtherFlag) IsSet() bool {
+	return f.isSet
+}
+
 // Value returns the value
 func (f *EtherFlag) Value() *big.Int {
 	return f.value
@@ -52,5 +71,5 @@ func (f *EtherFlag) Value() *big.Int {
 
 // Ether returns a new EtherFlag set to the given value, in wei
 func Ether(value *big.Int, u Unit) EtherFlag {
-	return EtherFlag{u, value}
+	return EtherFlag{unit: u, value: value}
 }
@@ -0,0 +1,36 @@
+package flags
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LibrariesFlag is a repeatable flag of `Name=0xaddress` library addresses
+type LibrariesFlag struct {
+	Value map[string]common.Address
+}
+
+func (f *LibrariesFlag) String() string {
+	s := make([]string, 0, len(f.Value))
+	for name, addr := range f.Value {
+		s = append(s, name+"="+addr.Hex())
+	}
+	return strings.Join(s, ",")
+}
+
+// Set adds the `Name=0xaddress` library
+func (f *LibrariesFlag) Set(value string) error {
+	i := strings.LastIndex(value, "=")
+	if i <= 0 {
+		return errors.New("Invalid library, expected Name=0xaddress")
+	} else if !common.IsHexAddress(value[i+1:]) {
+		return errors.New("Invalid library address")
+	}
+	if f.Value == nil {
+		f.Value = make(map[string]common.Address)
+	}
+	f.Value[value[:i]] = common.HexToAddress(value[i+1:])
+	return nil
+}
@@ -6,5 +6,6 @@ require (
 	github.com/allegro/bigcache v1.2.1 // indirect
 	github.com/ethereum/go-ethereum v1.10.23
 	github.com/rjeczalik/notify v0.9.2 // indirect
+	github.com/tyler-smith/go-bip39 v1.1.0
 	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519
 )
@@ -0,0 +1,127 @@
+// Package ledger keeps an offline record of the next nonce, per chain and address
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Entry is the next nonce of an address on a chain
+type Entry struct {
+	ChainID *big.Int
+	Address common.Address
+	Nonce   uint64
+}
+
+// Ledger is the next nonce, keyed by chain ID then address, stored as JSON.
+// An open Ledger holds an exclusive lock, so parallel invocations can't reuse a nonce, until closed.
+type Ledger struct {
+	path   string
+	lock   *os.File
+	nonces map[string]map[common.Address]uint64
+}
+
+// DefaultPath returns `~/.ethsign/nonces.json`
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ethsign", "nonces.json"), nil
+}
+
+// Open locks, and reads, the ledger at the path, creating its directory when missing
+func Open(path string) (*Ledger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	l := &Ledger{path, lock, make(map[string]map[common.Address]uint64)}
+	b, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	} else if err == nil {
+		err = json.Unmarshal(b, &l.nonces)
+	}
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Next returns the next nonce of the address on the chain, 0 when unknown
+func (l *Ledger) Next(chainID *big.Int, addr common.Address) uint64 {
+	return l.nonces[chainID.String()][addr]
+}
+
+// Set sets the next nonce of the address on the chain
+func (l *Ledger) Set(chainID *big.Int, addr common.Address, nonce uint64) {
+	id := chainID.String()
+	if l.nonces[id] == nil {
+		l.nonces[id] = make(map[common.Address]uint64)
+	}
+	l.nonces[id][addr] = nonce
+}
+
+// Used records the nonce as used, advancing the next nonce past it
+func (l *Ledger) Used(chainID *big.Int, addr common.Address, nonce uint64) {
+	if nonce >= l.Next(chainID, addr) {
+		l.Set(chainID, addr, nonce+1)
+	}
+}
+
+// Reset removes the address on the chain, so its next nonce is 0
+func (l *Ledger) Reset(chainID *big.Int, addr common.Address) {
+	id := chainID.String()
+	delete(l.nonces[id], addr)
+	if len(l.nonces[id]) == 0 {
+		delete(l.nonces, id)
+	}
+}
+
+// Entries returns every entry, ordered by chain ID then address
+func (l *Ledger) Entries() []Entry {
+	var entries []Entry
+	for id, addrs := range l.nonces {
+		chainID, _ := new(big.Int).SetString(id, 10)
+		for addr, nonce := range addrs {
+			entries = append(entries, Entry{chainID, addr, nonce})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if c := entries[i].ChainID.Cmp(entries[j].ChainID); c != 0 {
+			return c < 0
+		}
+		return entries[i].Address.Hex() < entries[j].Address.Hex()
+	})
+	return entries
+}
+
+// Save writes the ledger, replacing the file atomically
+func (l *Ledger) Save() error {
+	b, err := json.MarshalIndent(l.nonces, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := l.path + ".tmp"
+	if err = ioutil.WriteFile(tmp, append(b, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// Close releases the ledger's lock
+func (l *Ledger) Close() error {
+	return unlockFile(l.lock)
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+// +build !windows
+
+package ledger
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile opens, and exclusively locks, the file, blocking until the lock is acquired
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func unlockFile(f *os.File) error {
+	syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	return f.Close()
+}
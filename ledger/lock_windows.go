@@ -0,0 +1,86 @@
+//go:build windows
+// +build windows
+
+package ledger
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// staleLock is the age after which a lock file without a process ID, eg. from an older version, is taken to be
+// left behind
+const staleLock = time.Minute
+
+// stillActive is the exit code of running processes
+const stillActive = 259
+
+// lockFile exclusively creates the file, containing our process ID, retrying until it's acquired, or times out.
+// Lock files left behind by exited processes are removed, while Windows prevents removing those still open.
+func lockFile(path string) (*os.File, error) {
+	for i := 0; i < 300; i++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0600)
+		if err == nil {
+			if _, err = f.WriteString(strconv.Itoa(os.Getpid())); err != nil {
+				unlockFile(f)
+				return nil, err
+			}
+			return f, nil
+		} else if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if staleLockFile(path) && os.Remove(path) == nil {
+			continue
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil, errors.New("Timed out waiting for the nonce ledger lock, '" + path + "'")
+}
+
+// staleLockFile returns whether the process holding the lock has exited
+func staleLockFile(path string) bool {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		// The process ID may not have been written yet
+		fi, err := os.Stat(path)
+		return err == nil && time.Since(fi.ModTime()) > staleLock
+	}
+	return !processRunning(pid)
+}
+
+// processRunning returns whether the process is running
+func processRunning(pid int) bool {
+	h, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Processes of other users can't be queried, but exist
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err = syscall.GetExitCodeProcess(h, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
+
+// unlockFile closes, and removes, the lock file, unless it has since been replaced by another process' lock
+func unlockFile(f *os.File) error {
+	fi, err := f.Stat()
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if cur, err := os.Stat(f.Name()); err != nil || !os.SameFile(fi, cur) {
+		return nil
+	}
+	return os.Remove(f.Name())
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// isArrayKind returns whether the kind is an array, eg. `uint256[]`, `address[2][]`
+func isArrayKind(kind string) bool {
+	return strings.HasSuffix(kind, "]")
+}
+
+// splitArrayKind splits the outer-most dimension from the kind, returning the element kind and the
+// fixed size of the dimension, or -1 for dynamically sized dimensions.
+//
+//	`address[2][]` => `address[2]`, -1
+//	`address[2]`   => `address`, 2
+func splitArrayKind(kind string) (string, int, error) {
+	begin := strings.LastIndex(kind, "[")
+	if begin < 1 || !isArrayKind(kind) {
+		return "", 0, fmt.Errorf("Invalid array type '%s'", kind)
+	}
+	size := kind[begin+1 : len(kind)-1]
+	if size == "" {
+		return kind[:begin], -1, nil
+	}
+	n, err := strconv.ParseUint(size, 10, 32)
+	if err != nil || n == 0 {
+		return "", 0, fmt.Errorf("Invalid array size in type '%s'", kind)
+	}
+	return kind[:begin], int(n), nil
+}
+
+// typeForKind returns the Go type used to represent the kind, recursing into array dimensions
+func typeForKind(kind string) (reflect.Type, error) {
+	if isArrayKind(kind) {
+		elem, size, err := splitArrayKind(kind)
+		if err != nil {
+			return nil, err
+		}
+		t, err := typeForKind(elem)
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return reflect.SliceOf(t), nil
+		}
+		return reflect.ArrayOf(size, t), nil
+	}
+
+	// Get the type, using the default value
+	d, ok := defaults[kind]
+	if !ok && strings.HasPrefix(kind, "bytes") {
+		// Fixed-size bytes, zero-filled to their size
+		var size int
+		fmt.Sscanf(kind[5:], "%d", &size)
+		d = strings.Repeat("00", size)
+	} else if !ok {
+		d = "0"
+	}
+	v, err := parseValue(kind, d)
+	if err != nil {
+		return nil, err
+	}
+	return reflect.TypeOf(v), nil
+}
+
+// parseArray parses the value, eg. `[[1,2],[3]]`, into a slice, or array, for each dimension of the kind
+func parseArray(kind, value string) (reflect.Value, error) {
+	elemKind, size, err := splitArrayKind(kind)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	t, err := typeForKind(kind)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return makeArray(kind, t, size, value, func(v string) (reflect.Value, error) {
+		if isArrayKind(elemKind) {
+			return parseArray(elemKind, v)
+		}
+		p, err := parseValue(elemKind, v)
+		return reflect.ValueOf(p), err
+	})
+}
+
+// makeArray creates a slice, or fixed-size array when size isn't negative, of type t, populating it with
+// the elements of value, parsed using parseElem
+func makeArray(kind string, t reflect.Type, size int, value string, parseElem func(string) (reflect.Value, error)) (reflect.Value, error) {
+	elems, err := splitList(value, "[]")
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("Invalid value for '%s': %w", kind, err)
+	}
+
+	var o reflect.Value
+	if size < 0 {
+		o = reflect.MakeSlice(t, len(elems), len(elems))
+	} else if size != len(elems) {
+		return reflect.Value{}, fmt.Errorf("Invalid array length for '%s', expected %d got %d", kind, size, len(elems))
+	} else {
+		o = reflect.New(t).Elem()
+	}
+	for i := range elems {
+		v, err := parseElem(elems[i])
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		o.Index(i).Set(v)
+	}
+	return o, nil
+}
+
+// splitList splits the top-level elements of a list value enclosed by one of the given pairs of
+// brackets, eg. "[]()", leaving nested lists, and objects, intact. Double quotes group an element,
+// allowing commas and brackets within strings, and are removed, as is whitespace outside of quotes.
+func splitList(value, brackets string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	enclosed := false
+	for i := 0; i+1 < len(brackets) && len(value) >= 2; i += 2 {
+		if value[0] == brackets[i] && value[len(value)-1] == brackets[i+1] {
+			enclosed = true
+		}
+	}
+	if !enclosed {
+		return nil, fmt.Errorf("value must be enclosed in %s '%s'", brackets, value)
+	}
+	value = value[1 : len(value)-1]
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var elems []string
+	var elem strings.Builder
+	depth, quoted := 0, false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"':
+			quoted = !quoted
+			// Keep quotes within nested values, so they're handled when the nested value is parsed
+			if depth == 0 {
+				continue
+			}
+		case quoted:
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			continue
+		case c == '[' || c == '(' || c == '{':
+			depth++
+		case c == ']' || c == ')' || c == '}':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("mismatched brackets '%s'", value)
+			}
+		case c == ',' && depth == 0:
+			elems = append(elems, elem.String())
+			elem.Reset()
+			continue
+		}
+		elem.WriteByte(c)
+	}
+	if quoted {
+		return nil, fmt.Errorf("mismatched quotes '%s'", value)
+	} else if depth != 0 {
+		return nil, fmt.Errorf("mismatched brackets '%s'", value)
+	}
+	return append(elems, elem.String()), nil
+}
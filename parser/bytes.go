@@ -0,0 +1,40 @@
+package parser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PadBytes, when set, right-pads fixed-size bytes values shorter than their size with zeros,
+// the way Solidity pads `bytes32("abc")`. Otherwise values must match the size exactly.
+var PadBytes = false
+
+// decodeHex decodes the hex-string, with or without a `0x` prefix
+func decodeHex(v string) ([]byte, error) {
+	if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+		v = v[2:]
+	}
+	return hex.DecodeString(v)
+}
+
+// parseBytes parses the hex-string into a `[]byte` for `bytes`, or a `[N]byte` for `bytes1` to `bytes32`
+func parseBytes(kind, v string) (interface{}, error) {
+	h, err := decodeHex(v)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid bytes '%s': %w", v, err)
+	}
+	if kind == "bytes" {
+		return h, nil
+	}
+
+	var size int
+	fmt.Sscanf(kind[5:], "%d", &size)
+	if len(h) > size || (len(h) < size && !PadBytes) {
+		return nil, fmt.Errorf("Invalid %s length, expected %d got %d", kind, size, len(h))
+	}
+	o := reflect.New(reflect.ArrayOf(size, reflect.TypeOf(byte(0)))).Elem()
+	reflect.Copy(o, reflect.ValueOf(h))
+	return o.Interface(), nil
+}
@@ -0,0 +1,36 @@
+package parser
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ArgumentError is returned when a function, or constructor, argument can't be parsed to its type
+type ArgumentError struct {
+	Index int
+	Type  string
+	Err   error
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("Argument %d (%s): %v", e.Index, e.Type, e.Err)
+}
+
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// RangeError is returned when a number is outside the range of its `intN`, or `uintN`, type
+type RangeError struct {
+	Type  string
+	Value *big.Int
+	Min   *big.Int
+	Max   *big.Int
+}
+
+func (e *RangeError) Error() string {
+	if e.Min.Sign() == 0 && e.Value.Sign() < 0 {
+		return fmt.Sprintf("%s must be non-negative, got %s", e.Type, e.Value)
+	}
+	return fmt.Sprintf("%s overflows %s, must be between %s and %s", e.Value, e.Type, e.Min, e.Max)
+}
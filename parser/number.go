@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// units maps unit suffixes to their number of decimals, in wei
+var units = map[string]int{
+	"wei":        0,
+	"kwei":       3,
+	"babbage":    3,
+	"mwei":       6,
+	"lovelace":   6,
+	"gwei":       9,
+	"shannon":    9,
+	"szabo":      12,
+	"microether": 12,
+	"finney":     15,
+	"milliether": 15,
+	"ether":      18,
+	"kether":     21,
+	"grand":      21,
+	"mether":     24,
+	"gether":     27,
+	"tether":     30,
+}
+
+// ParseNumber parses an integer, or decimal, number literal. Unsuffixed values are scaled by the given
+// number of decimals, while unit suffixed values are scaled by the unit (eg. `gwei` scales by 9).
+// The result must be a whole number, once scaled.
+//
+//	42, -42, 1_000_000, 0xff, 0xdead_beef, 1e18, 1.5e3, 1.5ether, 250gwei, 250 gwei
+func ParseNumber(value string, decimals int) (*big.Int, error) {
+	s := strings.ToLower(strings.TrimSpace(value))
+	neg := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	var n *big.Int
+	var err error
+	if strings.HasPrefix(s, "0x") {
+		n, err = parseHexNumber(s[2:])
+	} else {
+		n, err = parseDecimalNumber(s, decimals)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid number '%s': %w", value, err)
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+func parseHexNumber(s string) (*big.Int, error) {
+	digits, err := removeUnderscores(s)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(digits, 16)
+	if !ok || strings.Trim(digits, "0123456789abcdef") != "" {
+		return nil, errors.New("invalid hex digits")
+	}
+	return n, nil
+}
+
+func parseDecimalNumber(s string, decimals int) (*big.Int, error) {
+	// Unit suffix
+	end := len(s)
+	for end > 0 && s[end-1] >= 'a' && s[end-1] <= 'z' {
+		end--
+	}
+	if suffix := s[end:]; suffix != "" && suffix != "e" {
+		d, ok := units[suffix]
+		if !ok {
+			return nil, fmt.Errorf("unknown unit '%s'", suffix)
+		}
+		decimals = d
+		s = strings.TrimSpace(s[:end])
+	}
+
+	// Exponent
+	exp := 0
+	if i := strings.IndexByte(s, 'e'); i >= 0 {
+		e, err := removeUnderscores(s[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		if exp, err = strconv.Atoi(e); err != nil || len(e) > 4 {
+			return nil, errors.New("invalid exponent")
+		}
+		s = s[:i]
+	}
+
+	// Integer and fractional digits
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" {
+		return nil, errors.New("missing digits")
+	}
+	whole, err := removeUnderscores(whole)
+	if err == nil {
+		frac, err = removeUnderscores(frac)
+	}
+	if err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok || strings.Trim(whole+frac, "0123456789") != "" {
+		return nil, errors.New("invalid digits")
+	}
+
+	// Scale, ensuring the result is whole
+	scale := exp + decimals - len(frac)
+	if scale >= 0 {
+		return n.Mul(n, pow10(scale)), nil
+	}
+	q, r := new(big.Int).QuoRem(n, pow10(-scale), new(big.Int))
+	if r.Sign() != 0 {
+		return nil, errors.New("not a whole number")
+	}
+	return q, nil
+}
+
+// removeUnderscores removes digit separators, ensuring they're only between digits
+func removeUnderscores(s string) (string, error) {
+	if strings.HasPrefix(s, "_") || strings.HasSuffix(s, "_") || strings.Contains(s, "__") {
+		return "", errors.New("misplaced '_' separator")
+	}
+	return strings.Replace(s, "_", "", -1), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// intKind returns the bit size, and signedness, of `intN`, and `uintN`, kinds, where N is 8 to 256 in steps
+// of 8. The aliases `int`, and `uint`, are 256 bits.
+func intKind(kind string) (int, bool, bool) {
+	signed := !strings.HasPrefix(kind, "uint")
+	size := strings.TrimPrefix(kind, "u")
+	if !strings.HasPrefix(size, "int") {
+		return 0, false, false
+	}
+	size = size[3:]
+	if size == "" {
+		return 256, signed, true
+	}
+	bits, err := strconv.Atoi(size)
+	if err != nil || bits < 8 || bits > 256 || bits%8 != 0 || size[0] == '0' {
+		return 0, false, false
+	}
+	return bits, signed, true
+}
+
+// checkRange ensures the number fits within the signed, or unsigned, bit size
+func checkRange(kind string, n *big.Int, signed bool, bits int) error {
+	var min, max *big.Int
+	if signed {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		min = new(big.Int).Neg(max)
+	} else {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(bits))
+		min = new(big.Int)
+	}
+	max.Sub(max, big.NewInt(1))
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		return &RangeError{kind, n, min, max}
+	}
+	return nil
+}
+
+// parseInt parses the number, ensuring it's within the range of the kind, returning the type go-ethereum's
+// ABI packer expects: `int8`...`int64`, `uint8`...`uint64` for those sizes, otherwise `*big.Int`
+func parseInt(kind string, bits int, signed bool, v string) (interface{}, error) {
+	n, err := ParseNumber(v, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkRange(kind, n, signed, bits); err != nil {
+		return nil, fmt.Errorf("Invalid number '%s': %w", v, err)
+	}
+	switch {
+	case bits == 8 && signed:
+		return int8(n.Int64()), nil
+	case bits == 16 && signed:
+		return int16(n.Int64()), nil
+	case bits == 32 && signed:
+		return int32(n.Int64()), nil
+	case bits == 64 && signed:
+		return n.Int64(), nil
+	case bits == 8:
+		return uint8(n.Uint64()), nil
+	case bits == 16:
+		return uint16(n.Uint64()), nil
+	case bits == 32:
+		return uint32(n.Uint64()), nil
+	case bits == 64:
+		return n.Uint64(), nil
+	}
+	return n, nil
+}
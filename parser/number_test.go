@@ -0,0 +1,97 @@
+package parser
+
+import "testing"
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		value    string
+		decimals int
+		want     string
+		err      bool
+	}{
+		// Integers
+		{value: "0", want: "0"},
+		{value: "42", want: "42"},
+		{value: "-42", want: "-42"},
+		{value: "+42", want: "42"},
+		{value: " 42 ", want: "42"},
+		{value: "42", decimals: 3, want: "42000"},
+
+		// Hex
+		{value: "0xff", want: "255"},
+		{value: "0XFF", want: "255"},
+		{value: "-0xff", want: "-255"},
+		{value: "0xdead_beef", want: "3735928559"},
+		{value: "0xff", decimals: 18, want: "255"},
+		{value: "0x", err: true},
+		{value: "0xfg", err: true},
+		{value: "0x_ff", err: true},
+
+		// Separators
+		{value: "1_000_000", want: "1000000"},
+		{value: "1_000.000_1", decimals: 4, want: "10000001"},
+		{value: "_1000", err: true},
+		{value: "1000_", err: true},
+		{value: "1__000", err: true},
+
+		// Exponents
+		{value: "1e18", want: "1000000000000000000"},
+		{value: "1E3", want: "1000"},
+		{value: "1.5e3", want: "1500"},
+		{value: "1e+3", want: "1000"},
+		{value: "1500e-3", decimals: 1, want: "15"},
+		{value: "1e1_0", want: "10000000000"},
+		{value: "1e", err: true},
+		{value: "1e5.5", err: true},
+		{value: "1e5-3", err: true},
+		{value: "1e5e5", err: true},
+		{value: "1e12345", err: true},
+		{value: "e5", err: true},
+
+		// Units
+		{value: "1ether", want: "1000000000000000000"},
+		{value: "1.5 ether", want: "1500000000000000000"},
+		{value: "250gwei", decimals: 18, want: "250000000000"},
+		{value: "250 GWEI", want: "250000000000"},
+		{value: "1wei", decimals: 18, want: "1"},
+		{value: "1e3 gwei", want: "1000000000000"},
+		{value: "1finney", want: "1000000000000000"},
+		{value: "1 parsec", err: true},
+		{value: "max", err: true},
+
+		// Decimals, which must be whole once scaled
+		{value: "1.5", decimals: 18, want: "1500000000000000000"},
+		{value: ".5", decimals: 1, want: "5"},
+		{value: "5.", want: "5"},
+		{value: "1.50", decimals: 1, want: "15"},
+		{value: "1.5", err: true},
+		{value: "1.5wei", err: true},
+		{value: "0.1gwei", want: "100000000"},
+		{value: "1e-1", err: true},
+
+		// Malformed
+		{value: "", err: true},
+		{value: "-", err: true},
+		{value: ".", err: true},
+		{value: "1.2.3", err: true},
+		{value: "--1", err: true},
+		{value: "-+1", err: true},
+		{value: "0x-ff", err: true},
+		{value: "1 2", err: true},
+		{value: "abc", err: true},
+	}
+	for _, tt := range tests {
+		n, err := ParseNumber(tt.value, tt.decimals)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseNumber(%q, %d): expected an error, got %s", tt.value, tt.decimals, n)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseNumber(%q, %d): unexpected error: %v", tt.value, tt.decimals, err)
+		} else if n.String() != tt.want {
+			t.Errorf("ParseNumber(%q, %d): expected %s, got %s", tt.value, tt.decimals, tt.want, n)
+		}
+	}
+}
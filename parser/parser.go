@@ -1,11 +1,9 @@
 package parser
 
 import (
-	"encoding/hex"
+	"bytes"
 	"errors"
 	"fmt"
-	"math/big"
-	"reflect"
 	"strconv"
 	"strings"
 
@@ -17,30 +15,22 @@ import (
 var defaults = map[string]string{
 	"address": "0x0000000000000000000000000000000000000000",
 	"bool":    "false",
+	"bytes":   "",
 	"string":  "",
 }
 
 // ParseValue parses the given value to the corresponding kind
 func ParseValue(kind, value string) (interface{}, error) {
-	begin := strings.Index(kind, "[")
 	// Non array
-	if begin < 0 {
+	if !isArrayKind(kind) {
 		return parseValue(kind, value)
 	}
-	// Array
-	k := kind[:begin]
-	args := kind[begin:]
-	var ok bool
-	ok, value = isValidArrayArgs(args, value)
-	if !ok {
-		return nil, errors.New("Invalid or mismatched array signature and/or value")
-	}
-	t, err := typeForKind(k)
+	// Array, of any dimension
+	o, err := parseArray(kind, value)
 	if err != nil {
 		return nil, err
 	}
-	o, err := parseArray(t, k, value)
-	return o, err
+	return o.Interface(), nil
 }
 
 // ParseConstructor parses the given args to the corresponding types found within the constructor, returning the raw data
@@ -73,114 +63,85 @@ func ParseMethod(method string, args []string) ([]byte, error) {
 	return append(sig, data...), nil
 }
 
-func parseMethodString(s string) ([]byte, []string, error) {
-	// Must have an open parent and be at-least "f()"
-	start, end := strings.Index(s, "("), strings.Index(s, ")")
-	if start < 1 || end < start || end != len(s)-1 || len(s) <= 3 {
-		return nil, nil, errors.New("Invalid call")
-	}
-	var args []string
-	if start+1 < end-1 {
-		args = strings.Split(s[start+1:end], ",")
+// ParseSignature returns the function of the method signature, eg. `transfer(address, uint)`, along with its
+// canonical signature and selector
+func ParseSignature(method string) (abi.Method, error) {
+	// Remove all whitespace – " test( string, bool)" => "test(string,bool)"
+	method = strings.Replace(method, " ", "", -1)
+	_, methodArgs, err := parseMethodString(method)
+	if err != nil {
+		return abi.Method{}, err
 	}
-	sig := crypto.Keccak256([]byte(s))[:4]
-	return sig, args, nil
+	name := method[:strings.Index(method, "(")]
+	return abi.NewMethod(name, name, abi.Function, "", false, false, methodArgs, nil), nil
 }
 
-func parseMethodArgs(types, args []string) ([]byte, error) {
-	if len(types) != len(args) {
-		return nil, fmt.Errorf("Mismatched length, expected %d got %d", len(types), len(args))
+// DecodeMethod unpacks the given call data to the corresponding types found within the method signature
+func DecodeMethod(method string, data []byte) ([]interface{}, error) {
+	// Remove all whitespace – " test( string, bool)" => "test(string,bool)"
+	method = strings.Replace(method, " ", "", -1)
+	sig, methodArgs, err := parseMethodString(method)
+	if err != nil {
+		return nil, err
 	}
-	var b []byte
-	for i := range types {
-		// Get parsed value
-		o, err := ParseValue(types[i], args[i])
-		if err != nil {
-			return b, err
-		}
-		// Pack the variable
-		t, err := abi.NewType(types[i], types[i], nil)
-		if err != nil {
-			return b, err
-		}
-		a := abi.Arguments{{Type: t}}
-		p, err := a.Pack(o)
-		if err != nil {
-			return b, err
-		}
-		b = append(b, p...)
+	if len(data) < 4 {
+		return nil, errors.New("Call data is missing a function selector")
+	} else if !bytes.Equal(sig, data[:4]) {
+		return nil, fmt.Errorf("Mismatched selector, '%s' is 0x%x, call data has 0x%x", method, sig, data[:4])
 	}
-	return b, nil
+	return methodArgs.Unpack(data[4:])
 }
 
-func isValidArrayArgs(signature, value string) (bool, string) {
-	// Very basic validation check ensuring signature and value end with a closing bracket, and the value also starts with an opening bracket
-	if len(signature) < 2 || len(value) < 2 {
-		return false, ""
-	} else if signature[len(signature)-1] != ']' {
-		return false, ""
-	} else if value[0] != '[' || value[len(value)-1] != ']' {
-		return false, ""
+func parseMethodString(s string) ([]byte, abi.Arguments, error) {
+	// Must have an open parent and be at-least "f()"
+	start, end := strings.Index(s, "("), strings.LastIndex(s, ")")
+	if start < 1 || end < start || end != len(s)-1 || len(s) < 3 {
+		return nil, nil, errors.New("Invalid call")
 	}
-
-	// Remove all non-quotes spaces (TODO: do we really need to check all other chars? tabs, etc.)
-	// Ensure '[' have matching ']'
-	b := true
-	start, end := 0, 0
-	v := make([]byte, len(value))
-	count := 0
-	for i := 0; i < len(value); i++ {
-		switch value[i] {
-		case '"':
-			b = !b
-			continue
-		case ' ':
-			if b {
-				continue
-			}
-		case '[':
-			start++
-			break
-		case ']':
-			end++
-			break
-		}
-		if start < 0 || end < 0 {
-			return false, ""
-		}
-		v[count] = value[i]
-		count++
+	types, err := splitTypes(s[start+1 : end])
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := parseArguments(types)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The selector is of the canonical signature – "f(uint,int[])" => "f(uint256,int256[])"
+	for i := range args {
+		types[i] = args[i].Type.String()
 	}
-	b = b && start-end == 0
-	return b, string(v[:count])
+	sig := crypto.Keccak256([]byte(s[:start] + "(" + strings.Join(types, ",") + ")"))[:4]
+	return sig, args, nil
 }
 
-func typeForKind(kind string) (reflect.Type, error) {
-	// Get the type, using the default value
-	d, ok := defaults[kind]
-	if !ok {
-		d = "0"
-	}
-	v, err := parseValue(kind, d)
-	if err != nil {
-		return nil, err
+// parseArguments creates the ABI arguments for the given types
+func parseArguments(types []string) (abi.Arguments, error) {
+	args := make(abi.Arguments, len(types))
+	for i := range types {
+		t, err := newType(types[i])
+		if err != nil {
+			return nil, err
+		}
+		args[i] = abi.Argument{Type: t}
 	}
-	return reflect.TypeOf(v), nil
+	return args, nil
 }
 
-func parseArray(t reflect.Type, kind, val string) (interface{}, error) {
-	// TODO: Support multi-dimensional arrays
-	// Create the array and populate it with the parsed valued
-	o := reflect.New(reflect.SliceOf(t)).Elem()
-	s := strings.Split(val[1:len(val)-1], ",")
-	for i := range s {
-		p, err := parseValue(kind, s[i])
+func parseMethodArgs(a abi.Arguments, args []string) ([]byte, error) {
+	if len(a) != len(args) {
+		return nil, fmt.Errorf("Mismatched length, expected %d got %d", len(a), len(args))
+	}
+	// Get parsed values
+	var err error
+	values := make([]interface{}, len(args))
+	for i := range a {
+		values[i], err = ParseArgument(a[i].Type, args[i])
 		if err != nil {
-			return o, err
+			return nil, &ArgumentError{i, a[i].Type.String(), err}
 		}
-		o = reflect.Append(o, reflect.ValueOf(p))
 	}
-	return o.Interface(), nil
+	// Pack the variables, together, so dynamic types are offset correctly
+	return a.Pack(values...)
 }
 
 func parseValue(s, v string) (interface{}, error) {
@@ -195,132 +156,23 @@ func parseValue(s, v string) (interface{}, error) {
 	case "bool":
 		t, err := strconv.ParseBool(v)
 		if err != nil {
-			return o, fmt.Errorf("Invalid bool '%s': %w", t, err)
+			return o, fmt.Errorf("Invalid bool '%s': %w", v, err)
 		}
 		o = t
 		break
 	case "string":
 		o = v
 		break
-	case "bytes1", "bytes2", "bytes3", "bytes4", "bytes5", "bytes6", "bytes7", "bytes8",
+	case "bytes", "bytes1", "bytes2", "bytes3", "bytes4", "bytes5", "bytes6", "bytes7", "bytes8",
 		"bytes9", "bytes10", "bytes11", "bytes12", "bytes13", "bytes14", "bytes15", "bytes16",
 		"bytes17", "bytes18", "bytes19", "bytes20", "bytes21", "bytes22", "bytes23", "bytes24",
 		"bytes25", "bytes26", "bytes27", "bytes28", "bytes29", "bytes30", "bytes31", "bytes32":
-		h, err := hex.DecodeString(v)
-		if err != nil {
-			return o, fmt.Errorf("Invalid bytes '%s': %w", h, err)
-		}
-		size, _ := strconv.ParseInt(s[5:], 10, 8)
-		if len(h) != int(size) {
-			return o, fmt.Errorf("Invalid bytes length, expected %d got %d", s[5:], len(h))
-		}
-		o = h
-		break
-	case "uint", "int",
-		"uint256", "int256",
-		"uint248", "int248",
-		"uint240", "int240",
-		"uint232", "int232",
-		"uint224", "int224",
-		"uint216", "int216",
-		"uint208", "int208",
-		"uint200", "int200",
-		"uint192", "int192",
-		"uint184", "int184",
-		"uint176", "int176",
-		"uint168", "int168",
-		"uint160", "int160",
-		"uint152", "int152",
-		"uint144", "int144",
-		"uint136", "int136",
-		"uint128", "int128",
-		"uint120", "int120",
-		"uint112", "int112",
-		"uint104", "int104",
-		"uint96", "int96",
-		"uint88", "int88",
-		"uint80", "int80",
-		"uint72", "int72":
-		n, ok := new(big.Int).SetString(v, 10)
-		if !ok {
-			return o, fmt.Errorf("Invalid number '%s'", v)
-		}
-		o = n
-		break
-	case "uint64", "uint56", "uint48", "uint40":
-		d, err := parseUint(v, s[4:], 8)
-		if err != nil {
-			return o, fmt.Errorf("Invalid number '%s': %w", v, err)
-		}
-		o = uint64(d)
-		break
-	case "uint32", "uint24":
-		d, err := parseUint(v, s[4:], 8)
-		if err != nil {
-			return o, fmt.Errorf("Invalid number '%s': %w", v, err)
-		}
-		o = uint32(d)
-		break
-	case "uint16":
-		d, err := parseUint(v, s[4:], 8)
-		if err != nil {
-			return o, fmt.Errorf("Invalid number '%s': %w", v, err)
-		}
-		o = uint16(d)
-		break
-	case "uint8":
-		d, err := parseUint(v, s[4:], 8)
-		if err != nil {
-			return o, fmt.Errorf("Invalid number '%s': %w", v, err)
-		}
-		o = uint8(d)
-		break
-	case "int64", "int56", "int48", "int40":
-		d, err := parseInt(v, s[4:], 64)
-		if err != nil {
-			return o, fmt.Errorf("Invalid number '%s': %w", v, err)
-		}
-		o = int64(d)
-		break
-	case "int32", "int24":
-		d, err := parseInt(v, s[4:], 32)
-		if err != nil {
-			return o, fmt.Errorf("Invalid number '%s': %w", v, err)
-		}
-		o = int32(d)
-		break
-	case "int16":
-		d, err := parseInt(v, s[4:], 16)
-		if err != nil {
-			return o, fmt.Errorf("Invalid number '%s': %w", v, err)
-		}
-		o = int16(d)
-		break
-	case "int8":
-		d, err := parseInt(v, s[4:], 8)
-		if err != nil {
-			return o, fmt.Errorf("Invalid number '%s': %w", v, err)
-		}
-		o = int8(d)
-		break
+		return parseBytes(s, v)
 	default:
+		if bits, signed, ok := intKind(s); ok {
+			return parseInt(s, bits, signed, v)
+		}
 		return o, fmt.Errorf("Invalid type '%s'", s)
 	}
 	return o, nil
 }
-
-func parseInt(value, base string, bitSize int) (int64, error) {
-	b, err := strconv.ParseInt(base, 10, bitSize)
-	if err != nil {
-		return 0, err
-	}
-	return strconv.ParseInt(value, 10, int(b))
-}
-
-func parseUint(value, base string, bitSize int) (uint64, error) {
-	b, err := strconv.ParseInt(base, 10, bitSize)
-	if err != nil {
-		return 0, err
-	}
-	return strconv.ParseUint(value, 10, int(b))
-}
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// ParseArgument parses the given value to the corresponding ABI type, including tuples (structs)
+//
+// Tuples may be given positionally, `[0x11..11,42]` or `(0x11..11,42)`, or as a JSON object keyed by
+// the tuple's component names, `{"to":"0x11..11","amount":42}`
+func ParseArgument(t abi.Type, value string) (interface{}, error) {
+	o, err := parseArgument(t, value)
+	if err != nil {
+		return nil, err
+	}
+	return o.Interface(), nil
+}
+
+func parseArgument(t abi.Type, value string) (reflect.Value, error) {
+	switch t.T {
+	case abi.TupleTy:
+		return parseTuple(t, value)
+	case abi.SliceTy, abi.ArrayTy:
+		size := -1
+		if t.T == abi.ArrayTy {
+			size = t.Size
+		}
+		return makeArray(t.String(), t.GetType(), size, value, func(v string) (reflect.Value, error) {
+			return parseArgument(*t.Elem, v)
+		})
+	}
+	o, err := parseValue(t.String(), value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return convertValue(o, t.GetType())
+}
+
+// parseTuple parses the value into the struct go-ethereum's ABI packer expects for the tuple
+func parseTuple(t abi.Type, value string) (reflect.Value, error) {
+	var elems []string
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{") {
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal([]byte(value), &fields); err != nil {
+			return reflect.Value{}, fmt.Errorf("Invalid value for '%s': %w", t.String(), err)
+		}
+		elems = make([]string, len(t.TupleRawNames))
+		for i, name := range t.TupleRawNames {
+			raw, ok := fields[name]
+			if !ok {
+				return reflect.Value{}, fmt.Errorf("Missing field '%s' for '%s'", name, t.String())
+			}
+			delete(fields, name)
+			elems[i] = string(raw)
+			// Unquote strings, leaving numbers, objects and arrays as they are
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				elems[i] = s
+			}
+		}
+		for name := range fields {
+			return reflect.Value{}, fmt.Errorf("Unknown field '%s' for '%s'", name, t.String())
+		}
+	} else {
+		var err error
+		elems, err = splitList(value, "()[]")
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("Invalid value for '%s': %w", t.String(), err)
+		} else if len(elems) != len(t.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("Invalid tuple length for '%s', expected %d got %d", t.String(), len(t.TupleElems), len(elems))
+		}
+	}
+
+	o := reflect.New(t.TupleType).Elem()
+	for i, elem := range t.TupleElems {
+		v, err := parseArgument(*elem, elems[i])
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		o.Field(i).Set(v)
+	}
+	return o, nil
+}
+
+// convertValue ensures a parsed value is the type go-ethereum's ABI packer expects
+func convertValue(o interface{}, t reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(o)
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("Invalid value, can't use %T as %s", o, t)
+	}
+	return v, nil
+}
+
+// newType creates the ABI type for the kind, including tuples, eg. `(address,(uint256,bool)[])[2]`
+func newType(kind string) (abi.Type, error) {
+	m, err := argumentMarshaling("", kind)
+	if err != nil {
+		return abi.Type{}, err
+	}
+	return abi.NewType(m.Type, "", m.Components)
+}
+
+// argumentMarshaling converts tuple kinds to `tuple` types, with components named `arg0`, `arg1`, ...
+func argumentMarshaling(name, kind string) (abi.ArgumentMarshaling, error) {
+	m := abi.ArgumentMarshaling{Name: name, Type: kind}
+	if !strings.HasPrefix(kind, "(") {
+		// Aliases, `uint` and `int`, are 256 bits
+		base := strings.SplitN(kind, "[", 2)
+		if base[0] == "uint" || base[0] == "int" {
+			m.Type = base[0] + "256" + kind[len(base[0]):]
+		}
+		return m, nil
+	}
+	end := strings.LastIndex(kind, ")")
+	if end < 0 || strings.ContainsAny(kind[end+1:], "()") {
+		return m, fmt.Errorf("Invalid tuple type '%s'", kind)
+	}
+	kinds, err := splitTypes(kind[1:end])
+	if err != nil {
+		return m, err
+	}
+	m.Type = "tuple" + kind[end+1:]
+	for i := range kinds {
+		c, err := argumentMarshaling(fmt.Sprintf("arg%d", i), kinds[i])
+		if err != nil {
+			return m, err
+		}
+		m.Components = append(m.Components, c)
+	}
+	return m, nil
+}
+
+// splitTypes splits a comma separated list of types, leaving tuple types intact
+//
+//	`address,(uint256,bool)[],bytes` => `address`, `(uint256,bool)[]`, `bytes`
+func splitTypes(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var kinds []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				kinds = append(kinds, s[start:i])
+				start = i + 1
+			}
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("Mismatched parentheses '%s'", s)
+	}
+	return append(kinds, s[start:]), nil
+}
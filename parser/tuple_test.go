@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const (
+	addr1 = "0x1111111111111111111111111111111111111111"
+	addr2 = "0x2222222222222222222222222222222222222222"
+)
+
+// words returns the hex of the 32-byte words, each left-padded with zeros
+func words(w ...string) string {
+	var b strings.Builder
+	for _, s := range w {
+		b.WriteString(strings.Repeat("0", 64-len(s)) + s)
+	}
+	return b.String()
+}
+
+func TestParseMethodTuples(t *testing.T) {
+	a1, a2 := strings.TrimPrefix(addr1, "0x"), strings.TrimPrefix(addr2, "0x")
+	tests := []struct {
+		method string
+		args   []string
+		want   string
+		err    bool
+	}{
+		{
+			method: "f((address,uint256),bool)",
+			args:   []string{"(" + addr1 + ",42)", "true"},
+			want:   "3186a400" + words(a1, "2a", "1"),
+		},
+		{
+			method: "f((address,uint256),bool)",
+			args:   []string{"[" + addr1 + ", 42]", "false"},
+			want:   "3186a400" + words(a1, "2a", "0"),
+		},
+		{
+			method: "f((address,uint256)[])",
+			args:   []string{"[(" + addr1 + ",1),(" + addr2 + ",2)]"},
+			want:   "0aaac967" + words("20", "2", a1, "1", a2, "2"),
+		},
+		{
+			method: "f((address,uint256)[])",
+			args:   []string{"[]"},
+			want:   "0aaac967" + words("20", "0"),
+		},
+		{
+			method: "f((address,(uint256,bool)))",
+			args:   []string{"(" + addr1 + ",(0x2a,true))"},
+			want:   "f2f3415b" + words(a1, "2a", "1"),
+		},
+		{
+			method: "f((address,uint256),bool)",
+			args:   []string{"(" + addr1 + ")", "true"},
+			err:    true,
+		},
+		{
+			method: "f((address,uint256),bool)",
+			args:   []string{"(" + addr1 + ",42,1)", "true"},
+			err:    true,
+		},
+		{
+			method: "f((address,uint256),bool)",
+			args:   []string{"(" + addr1 + ",-1)", "true"},
+			err:    true,
+		},
+		{
+			method: "f((address,uint256)[])",
+			args:   []string{"[(" + addr1 + ",1),(" + addr2 + ")]"},
+			err:    true,
+		},
+	}
+	for _, tt := range tests {
+		data, err := ParseMethod(tt.method, tt.args)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s %v: expected an error, got %x", tt.method, tt.args, data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %v: unexpected error: %v", tt.method, tt.args, err)
+		} else if got := hex.EncodeToString(data); got != tt.want {
+			t.Errorf("%s %v: expected\n%s, got\n%s", tt.method, tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestParseArgumentJSONTuples(t *testing.T) {
+	a1, a2 := strings.TrimPrefix(addr1, "0x"), strings.TrimPrefix(addr2, "0x")
+
+	// (address to, (uint256 value, bool flag) inner), as given by an ABI
+	components := []abi.ArgumentMarshaling{
+		{Name: "to", Type: "address"},
+		{Name: "inner", Type: "tuple", Components: []abi.ArgumentMarshaling{
+			{Name: "value", Type: "uint256"},
+			{Name: "flag", Type: "bool"},
+		}},
+	}
+	tuple, err := abi.NewType("tuple", "", components)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tuples, err := abi.NewType("tuple[]", "", components)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		t     abi.Type
+		value string
+		want  string
+		err   bool
+	}{
+		{
+			name:  "nested object",
+			t:     tuple,
+			value: `{"to": "` + addr1 + `", "inner": {"value": 42, "flag": true}}`,
+			want:  words(a1, "2a", "1"),
+		},
+		{
+			name:  "nested object, fields in any order",
+			t:     tuple,
+			value: `{"inner": {"flag": false, "value": "1e3"}, "to": "` + addr1 + `"}`,
+			want:  words(a1, "3e8", "0"),
+		},
+		{
+			name:  "object, with a positional nested tuple",
+			t:     tuple,
+			value: `{"to": "` + addr1 + `", "inner": "(42,true)"}`,
+			want:  words(a1, "2a", "1"),
+		},
+		{
+			name:  "array of objects",
+			t:     tuples,
+			value: `[{"to": "` + addr1 + `", "inner": {"value": 1, "flag": true}}, {"to": "` + addr2 + `", "inner": {"value": 2, "flag": false}}]`,
+			want:  words("20", "2", a1, "1", "1", a2, "2", "0"),
+		},
+		{
+			name:  "missing field",
+			t:     tuple,
+			value: `{"to": "` + addr1 + `"}`,
+			err:   true,
+		},
+		{
+			name:  "unknown field",
+			t:     tuple,
+			value: `{"to": "` + addr1 + `", "inner": {"value": 1, "flag": true, "extra": 1}}`,
+			err:   true,
+		},
+		{
+			name:  "invalid JSON",
+			t:     tuple,
+			value: `{"to": "` + addr1 + `",}`,
+			err:   true,
+		},
+	}
+	for _, tt := range tests {
+		v, err := ParseArgument(tt.t, tt.value)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		data, err := abi.Arguments{{Type: tt.t}}.Pack(v)
+		if err != nil {
+			t.Errorf("%s: unexpected pack error: %v", tt.name, err)
+		} else if got := hex.EncodeToString(data); got != tt.want {
+			t.Errorf("%s: expected\n%s, got\n%s", tt.name, tt.want, got)
+		}
+	}
+}
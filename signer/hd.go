@@ -0,0 +1,113 @@
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// HDWallet derives BIP-32 keys from a BIP-39 mnemonic
+type HDWallet struct {
+	key       []byte
+	chainCode []byte
+}
+
+// NewHDWallet returns the HD wallet for the BIP-39 mnemonic, and optional passphrase
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("Invalid mnemonic")
+	}
+	return newHDWallet(bip39.NewSeed(mnemonic, passphrase))
+}
+
+// newHDWallet returns the HD wallet of the BIP-32 master key, and chain code, for the seed
+func newHDWallet(seed []byte) (*HDWallet, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	i := mac.Sum(nil)
+	if !validPrivateKey(new(big.Int).SetBytes(i[:32])) {
+		return nil, errors.New("Invalid master key for mnemonic")
+	}
+	return &HDWallet{i[:32], i[32:]}, nil
+}
+
+// FromMnemonic returns a Signer for the key derived from the BIP-39 mnemonic, and optional passphrase, at
+// the BIP-32 path, eg. `m/44'/60'/0'/0/0`
+func FromMnemonic(mnemonic, passphrase string, path accounts.DerivationPath) (*Key, error) {
+	w, err := NewHDWallet(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return w.Derive(path)
+}
+
+// Derive returns a Signer for the key at the BIP-32 path
+func (w *HDWallet) Derive(path accounts.DerivationPath) (*Key, error) {
+	key, chainCode := w.key, w.chainCode
+	for _, index := range path {
+		var err error
+		key, chainCode, err = deriveChild(key, chainCode, index)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to derive '%s': %w", path, err)
+		}
+	}
+	k, err := crypto.ToECDSA(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(k), nil
+}
+
+// deriveChild returns the BIP-32 child private key, and chain code, for the index
+func deriveChild(key, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	var data []byte
+	if index >= 0x80000000 {
+		// Hardened, 0x00 || ser256(k) || ser32(i)
+		data = append([]byte{0}, key...)
+	} else {
+		// Normal, serP(point(k)) || ser32(i)
+		k, err := crypto.ToECDSA(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&k.PublicKey)
+	}
+	var i32 [4]byte
+	binary.BigEndian.PutUint32(i32[:], index)
+	data = append(data, i32[:]...)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	i := mac.Sum(nil)
+
+	// k(i) = parse256(IL) + k (mod n)
+	il := new(big.Int).SetBytes(i[:32])
+	if il.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, nil, errors.New("invalid child key")
+	}
+	child := il.Add(il, new(big.Int).SetBytes(key))
+	child.Mod(child, crypto.S256().Params().N)
+	if !validPrivateKey(child) {
+		return nil, nil, errors.New("invalid child key")
+	}
+	return leftPad32(child.Bytes()), i[32:], nil
+}
+
+func validPrivateKey(k *big.Int) bool {
+	return k.Sign() > 0 && k.Cmp(crypto.S256().Params().N) < 0
+}
+
+func leftPad32(b []byte) []byte {
+	p := make([]byte, 32)
+	copy(p[32-len(b):], b)
+	return p
+}
@@ -0,0 +1,73 @@
+package signer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+)
+
+func TestFromMnemonic(t *testing.T) {
+	tests := []struct {
+		mnemonic   string
+		passphrase string
+		path       string
+		address    string
+	}{
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "", "m/44'/60'/0'/0/0", "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{"test test test test test test test test test test test junk", "", "m/44'/60'/0'/0/0", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+		{"test test test test test test test test test test test junk", "", "m/44'/60'/0'/0/1", "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"},
+		{"test test test test test test test test test test test junk", "", "m/44'/60'/0'/0/2", "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"},
+		// Whitespace within the mnemonic is normalized
+		{"  test test test test test test\ntest test test test test junk ", "", "m/44'/60'/0'/0/0", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"},
+	}
+	for _, tt := range tests {
+		path, err := accounts.ParseDerivationPath(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, err := FromMnemonic(tt.mnemonic, tt.passphrase, path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.path, err)
+		} else if got := k.Address().Hex(); got != tt.address {
+			t.Errorf("%s: expected %s, got %s", tt.path, tt.address, got)
+		}
+	}
+
+	if _, err := NewHDWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ""); err == nil {
+		t.Error("expected an invalid mnemonic checksum to fail")
+	}
+}
+
+// TestDeriveChild checks BIP-32 test vector 1, of a hardened, then a non-hardened, child
+func TestDeriveChild(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	w, err := newHDWallet(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path      string
+		index     uint32
+		key       string
+		chainCode string
+	}{
+		{"m", 0, "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+		{"m/0'", 0x80000000, "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{"m/0'/1", 1, "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+	}
+	key, chainCode := w.key, w.chainCode
+	for i, tt := range tests {
+		if i > 0 {
+			if key, chainCode, err = deriveChild(key, chainCode, tt.index); err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.path, err)
+			}
+		}
+		if got := hex.EncodeToString(key); got != tt.key {
+			t.Errorf("%s: expected key %s, got %s", tt.path, tt.key, got)
+		}
+		if got := hex.EncodeToString(chainCode); got != tt.chainCode {
+			t.Errorf("%s: expected chain code %s, got %s", tt.path, tt.chainCode, got)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package signer provides the keys used to sign transactions, hashes and messages
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions, hashes and messages for a single address
+type Signer interface {
+	// Address returns the address of the signing key
+	Address() common.Address
+	// SignTx signs the transaction for the chain
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignHash signs the 32-byte hash, returning the 65-byte [R || S || V] signature, where V is 0 or 1
+	SignHash(hash []byte) ([]byte, error)
+	// SignMessage signs the EIP-191 `personal_sign` hash of the message, returning the 65-byte
+	// [R || S || V] signature, where V is 0 or 1
+	SignMessage(msg []byte) ([]byte, error)
+}
+
+// Key is an in-memory private key Signer
+type Key struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKey returns a Signer for the private key
+func NewKey(key *ecdsa.PrivateKey) *Key {
+	return &Key{key}
+}
+
+// FromHex returns a Signer for the hex-encoded private key, with, or without, a `0x` prefix.
+// Surrounding whitespace, eg. a trailing newline, is ignored.
+func FromHex(s string) (*Key, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	k, err := crypto.HexToECDSA(s)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid private key: %w", err)
+	}
+	return NewKey(k), nil
+}
+
+// FromFile returns a Signer for the file containing a hex-encoded private key
+func FromFile(path string) (*Key, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(b)), "{") {
+		return nil, fmt.Errorf("'%s' looks like a keystore file, not a raw private key", path)
+	}
+	return FromHex(string(b))
+}
+
+// FromKeystore returns a Signer for the go-ethereum encrypted keystore file
+func FromKeystore(path, passphrase string) (*Key, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	k, err := keystore.DecryptKey(b, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(k.PrivateKey), nil
+}
+
+// FromEnv returns a Signer for the hex-encoded private key within the environment variable
+func FromEnv(name string) (*Key, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(v) == "" {
+		return nil, fmt.Errorf("Environment variable '%s' is not set", name)
+	}
+	return FromHex(v)
+}
+
+// Address returns the address of the key
+func (k *Key) Address() common.Address {
+	return crypto.PubkeyToAddress(k.key.PublicKey)
+}
+
+// SignTx signs the transaction, of any type, for the chain
+func (k *Key) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, errors.New("Must specify a chain ID to sign the transaction for")
+	}
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), k.key)
+}
+
+// SignHash signs the 32-byte hash
+func (k *Key) SignHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, k.key)
+}
+
+// SignMessage signs the EIP-191 `personal_sign` hash of the message
+func (k *Key) SignMessage(msg []byte) ([]byte, error) {
+	return k.SignHash(accounts.TextHash(msg))
+}
+
+// RecoverHash returns the address which signed the 32-byte hash, with the 65-byte [R || S || V] signature,
+// where V is either 0/1 or 27/28
+func RecoverHash(hash, sig []byte) (common.Address, error) {
+	if len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("Invalid signature length, expected %d got %d", crypto.SignatureLength, len(sig))
+	}
+	s := make([]byte, len(sig))
+	copy(s, sig)
+	if s[64] >= 27 {
+		s[64] -= 27
+	}
+	pub, err := crypto.SigToPub(hash, s)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// RecoverMessage returns the address which signed the EIP-191 `personal_sign` hash of the message
+func RecoverMessage(msg, sig []byte) (common.Address, error) {
+	return RecoverHash(accounts.TextHash(msg), sig)
+}
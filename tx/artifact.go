@@ -0,0 +1,154 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Artifact is a compiled contract's ABI and creation bytecode, from a Hardhat artifact, Foundry `out/*.json`
+// artifact, or `solc --combined-json abi,bin` file
+type Artifact struct {
+	Name string
+	ABI  abi.ABI
+	// Bin is the hex-encoded creation bytecode, which may contain library placeholders
+	Bin string
+	// Libraries are the fully qualified names, eg. `contracts/Math.sol:Math`, of the linked libraries
+	Libraries []string
+}
+
+// linkReferences are the byte offsets of each library, keyed by source file and library name
+type linkReferences map[string]map[string][]struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+type artifactJSON struct {
+	ContractName string          `json:"contractName"`
+	ABI          json.RawMessage `json:"abi"`
+	// Bytecode is a string for Hardhat, and an object for Foundry
+	Bytecode       json.RawMessage `json:"bytecode"`
+	LinkReferences linkReferences  `json:"linkReferences"`
+	Contracts      map[string]struct {
+		ABI json.RawMessage `json:"abi"`
+		Bin string          `json:"bin"`
+	} `json:"contracts"`
+}
+
+// ReadArtifact reads the contract artifact. The contract name is required for a `solc --combined-json` file
+// containing more than one contract, and is otherwise checked against the artifact's contract name, when given.
+func ReadArtifact(path, contract string) (*Artifact, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeArtifact(b, contract)
+}
+
+// DecodeArtifact decodes the contract artifact, see ReadArtifact
+func DecodeArtifact(b []byte, contract string) (*Artifact, error) {
+	var j artifactJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return nil, fmt.Errorf("Invalid artifact: %w", err)
+	}
+	if j.Contracts != nil {
+		return combinedArtifact(j, contract)
+	} else if j.ABI == nil {
+		return nil, errors.New("Invalid artifact: missing 'abi'")
+	} else if contract != "" && j.ContractName != "" && contract != j.ContractName {
+		return nil, fmt.Errorf("Artifact is for contract '%s', not '%s'", j.ContractName, contract)
+	}
+
+	a := &Artifact{Name: j.ContractName}
+	var err error
+	if a.ABI, err = decodeABI(j.ABI); err != nil {
+		return nil, err
+	}
+
+	// Hardhat bytecode is a string, Foundry's an object including the link references
+	links := j.LinkReferences
+	if len(j.Bytecode) > 0 && j.Bytecode[0] == '{' {
+		var bytecode struct {
+			Object         string         `json:"object"`
+			LinkReferences linkReferences `json:"linkReferences"`
+		}
+		if err = json.Unmarshal(j.Bytecode, &bytecode); err != nil {
+			return nil, fmt.Errorf("Invalid artifact bytecode: %w", err)
+		}
+		a.Bin, links = bytecode.Object, bytecode.LinkReferences
+	} else if len(j.Bytecode) > 0 {
+		if err = json.Unmarshal(j.Bytecode, &a.Bin); err != nil {
+			return nil, fmt.Errorf("Invalid artifact bytecode: %w", err)
+		}
+	}
+	for file, libs := range links {
+		for lib := range libs {
+			a.Libraries = append(a.Libraries, file+":"+lib)
+		}
+	}
+	sort.Strings(a.Libraries)
+	return a, nil
+}
+
+// combinedArtifact returns the contract of the `solc --combined-json` output, by either its name, or fully
+// qualified name. Any of the file's contracts may be linked libraries.
+func combinedArtifact(j artifactJSON, contract string) (*Artifact, error) {
+	var names, matches []string
+	for name := range j.Contracts {
+		names = append(names, name)
+		if contract == name || contract == libraryName(name) || (contract == "" && len(j.Contracts) == 1) {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(names)
+	if len(matches) == 0 && contract == "" {
+		return nil, fmt.Errorf("Must specify one of the artifact's contracts: %s", strings.Join(names, ", "))
+	} else if len(matches) == 0 {
+		return nil, fmt.Errorf("Contract '%s' not found, expected one of: %s", contract, strings.Join(names, ", "))
+	} else if len(matches) > 1 {
+		sort.Strings(matches)
+		return nil, fmt.Errorf("Contract '%s' is ambiguous, expected one of: %s", contract, strings.Join(matches, ", "))
+	}
+
+	c := j.Contracts[matches[0]]
+	a := &Artifact{Name: matches[0], Bin: c.Bin, Libraries: names}
+	var err error
+	a.ABI, err = decodeABI(c.ABI)
+	return a, err
+}
+
+// decodeABI decodes the ABI, given either as JSON, or as a JSON string (older solc combined-json output)
+func decodeABI(raw json.RawMessage) (abi.ABI, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return abi.ABI{}, err
+		}
+		raw = []byte(s)
+	}
+	a, err := abi.JSON(bytes.NewReader(raw))
+	if err != nil {
+		return a, fmt.Errorf("Invalid artifact ABI: %w", err)
+	}
+	return a, nil
+}
+
+// Link returns the creation bytecode, with the library placeholders replaced, see LinkBin
+func (a *Artifact) Link(libs map[string]common.Address) ([]byte, error) {
+	if strings.TrimPrefix(strings.TrimSpace(a.Bin), "0x") == "" {
+		return nil, errors.New("Artifact has no creation bytecode, it may be an interface or abstract contract")
+	}
+	names := make(map[string]string)
+	for _, name := range a.Libraries {
+		names[placeholderHash(name)] = name
+	}
+	return link(a.Bin, names, libs)
+}
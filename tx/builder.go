@@ -0,0 +1,238 @@
+// Package tx builds, and signs, Ethereum transactions offline
+package tx
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/juztin/ethsign/signer"
+)
+
+// Option configures a Builder
+type Option func(*Builder) error
+
+// Builder builds a legacy, EIP-2930 access list, or EIP-1559 dynamic fee transaction, depending on
+// whether an access list, and/or dynamic fees, are given
+type Builder struct {
+	chainID        *big.Int
+	nonce          uint64
+	gasLimit       uint64
+	gasPrice       *big.Int
+	maxFee         *big.Int
+	maxPriorityFee *big.Int
+	accessList     types.AccessList
+	to             *common.Address
+	deploy         bool
+	value          *big.Int
+	data           []byte
+	signer         signer.Signer
+}
+
+// New returns a Builder configured with the given options
+func New(opts ...Option) (*Builder, error) {
+	b := &Builder{
+		chainID:  big.NewInt(1337),
+		gasLimit: 100000,
+		gasPrice: big.NewInt(1000000000),
+		value:    new(big.Int),
+	}
+	for _, o := range opts {
+		if err := o(b); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// WithChainID sets the EIP-155 chain ID (default 1337)
+func WithChainID(id *big.Int) Option {
+	return func(b *Builder) error {
+		if id == nil || id.Sign() <= 0 {
+			return errors.New("Chain ID must be positive")
+		}
+		b.chainID = id
+		return nil
+	}
+}
+
+// WithNonce sets the nonce (default 0)
+func WithNonce(nonce uint64) Option {
+	return func(b *Builder) error {
+		b.nonce = nonce
+		return nil
+	}
+}
+
+// WithGasLimit sets the gas limit (default 100000)
+func WithGasLimit(limit uint64) Option {
+	return func(b *Builder) error {
+		b.gasLimit = limit
+		return nil
+	}
+}
+
+// WithGasPrice sets the legacy gas price, in wei (default 1 Gwei)
+func WithGasPrice(price *big.Int) Option {
+	return func(b *Builder) error {
+		if price == nil || price.Sign() < 0 {
+			return errors.New("Gas price must be non-negative")
+		}
+		b.gasPrice = price
+		return nil
+	}
+}
+
+// WithDynamicFees sets the EIP-1559 max fee, and max priority fee, per gas, in wei
+func WithDynamicFees(maxFee, maxPriorityFee *big.Int) Option {
+	return func(b *Builder) error {
+		if maxFee == nil || maxPriorityFee == nil || maxFee.Sign() < 0 || maxPriorityFee.Sign() < 0 {
+			return errors.New("Max fee, and max priority fee, per gas must be non-negative")
+		} else if maxPriorityFee.Cmp(maxFee) > 0 {
+			return errors.New("Max priority fee per gas can't exceed max fee per gas")
+		}
+		b.maxFee, b.maxPriorityFee = maxFee, maxPriorityFee
+		return nil
+	}
+}
+
+// WithAccessList sets the EIP-2930 access list
+func WithAccessList(list types.AccessList) Option {
+	return func(b *Builder) error {
+		b.accessList = list
+		return nil
+	}
+}
+
+// WithTo sets the recipient, either of ether, or the contract being called
+func WithTo(to common.Address) Option {
+	return func(b *Builder) error {
+		if b.deploy {
+			return errors.New("Recipient can't be set for contract deployment")
+		}
+		b.to = &to
+		return nil
+	}
+}
+
+// WithValue sets the amount of ether, in wei, sent with the transaction
+func WithValue(value *big.Int) Option {
+	return func(b *Builder) error {
+		if value == nil || value.Sign() < 0 {
+			return errors.New("Can't send negative Ether")
+		}
+		b.value = value
+		return nil
+	}
+}
+
+// WithData sets the raw transaction data
+func WithData(data []byte) Option {
+	return func(b *Builder) error {
+		b.data = data
+		return nil
+	}
+}
+
+// WithMethod sets the data to a call of the method signature, eg. `transfer(address,uint256)`
+func WithMethod(method string, args ...string) Option {
+	return func(b *Builder) (err error) {
+		b.data, err = MethodData(method, args...)
+		return err
+	}
+}
+
+// WithABIMethod sets the data to a call of the ABI function
+func WithABIMethod(a abi.ABI, name string, args ...string) Option {
+	return func(b *Builder) (err error) {
+		b.data, err = ABIMethodData(a, name, args...)
+		return err
+	}
+}
+
+// WithDeployment makes the transaction a contract creation, from the bytecode, and the data returned by
+// one of DeployData or ABIDeployData
+func WithDeployment(data []byte) Option {
+	return func(b *Builder) error {
+		if b.to != nil {
+			return errors.New("Recipient can't be set for contract deployment")
+		}
+		b.deploy = true
+		b.data = data
+		return nil
+	}
+}
+
+// WithSigner sets the Signer used to sign the transaction
+func WithSigner(s signer.Signer) Option {
+	return func(b *Builder) error {
+		b.signer = s
+		return nil
+	}
+}
+
+// WithKey sets the private key used to sign the transaction
+func WithKey(key *ecdsa.PrivateKey) Option {
+	return WithSigner(signer.NewKey(key))
+}
+
+// Build returns the unsigned transaction
+func (b *Builder) Build() (*types.Transaction, error) {
+	if !b.deploy && b.to == nil {
+		return nil, errors.New("Must specify a recipient, or a contract deployment")
+	}
+	if b.maxFee != nil {
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:    b.chainID,
+			Nonce:      b.nonce,
+			GasTipCap:  b.maxPriorityFee,
+			GasFeeCap:  b.maxFee,
+			Gas:        b.gasLimit,
+			To:         b.to,
+			Value:      b.value,
+			Data:       b.data,
+			AccessList: b.accessList,
+		}), nil
+	} else if b.accessList != nil {
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    b.chainID,
+			Nonce:      b.nonce,
+			GasPrice:   b.gasPrice,
+			Gas:        b.gasLimit,
+			To:         b.to,
+			Value:      b.value,
+			Data:       b.data,
+			AccessList: b.accessList,
+		}), nil
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    b.nonce,
+		GasPrice: b.gasPrice,
+		Gas:      b.gasLimit,
+		To:       b.to,
+		Value:    b.value,
+		Data:     b.data,
+	}), nil
+}
+
+// Sign builds, and signs, the transaction, returning it along with its raw bytes.
+// Typed transactions keep their `type || payload` envelope.
+func (b *Builder) Sign() (*types.Transaction, []byte, error) {
+	if b.signer == nil {
+		return nil, nil, errors.New("Must specify a signer to sign the transaction with")
+	}
+	tx, err := b.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	tx, err = b.signer.SignTx(tx, b.chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := tx.MarshalBinary()
+	return tx, raw, err
+}
@@ -0,0 +1,31 @@
+package tx
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DeterministicDeployer is the deterministic deployment proxy factory, at the same address on most chains,
+// which CREATE2 deploys the init code following the 32-byte salt of the call data
+var DeterministicDeployer = common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+
+// WithCreate2 makes the transaction a call to the CREATE2 factory, eg. DeterministicDeployer, deploying the
+// init code, the data returned by one of DeployData or ABIDeployData, with the salt
+func WithCreate2(factory common.Address, salt common.Hash, initCode []byte) Option {
+	return func(b *Builder) error {
+		if b.deploy {
+			return errors.New("CREATE2 deployments are a call to the factory, not a contract creation")
+		}
+		b.to = &factory
+		b.data = append(salt.Bytes(), initCode...)
+		return nil
+	}
+}
+
+// Create2Address returns the address of the contract deployed by the factory, with the salt and init code,
+// `keccak256(0xff ++ factory ++ salt ++ keccak256(initCode))[12:]`
+func Create2Address(factory common.Address, salt common.Hash, initCode []byte) common.Address {
+	return crypto.CreateAddress2(factory, salt, crypto.Keccak256(initCode))
+}
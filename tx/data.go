@@ -0,0 +1,157 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/juztin/ethsign/encoding"
+	"github.com/juztin/ethsign/parser"
+)
+
+// intAliasRe matches the `uint` and `int` aliases of `uint256` and `int256`
+var intAliasRe = regexp.MustCompile(`\b(u?int)([,)\[])`)
+
+// ReadABI reads the contract ABI file
+func ReadABI(abiFile string) (abi.ABI, error) {
+	r, err := os.Open(abiFile)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	defer r.Close()
+	return abi.JSON(r)
+}
+
+// ReadBin reads the hex-encoded, compiled, contract bytecode file
+// Library placeholders must be linked, with ReadLinkedBin.
+func ReadBin(binFile string) ([]byte, error) {
+	return ReadLinkedBin(binFile, nil)
+}
+
+// MethodData returns the call data for the method signature, eg. `transfer(address,uint256)`, and args
+func MethodData(method string, args ...string) ([]byte, error) {
+	return parser.ParseMethod(method, args)
+}
+
+// ABIMethodData returns the call data for the ABI function, or the constructor when name is empty, and args.
+// Functions are selected as with FindMethod.
+func ABIMethodData(a abi.ABI, name string, args ...string) ([]byte, error) {
+	// Ensure the given function exists within the ABI
+	m := a.Constructor
+	if name != "" {
+		var err error
+		if m, err = FindMethod(a, name, len(args)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Convert args to matching types
+	funcArgs, err := encoding.DecodeArgs(m.Inputs, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate packed call
+	if name == "" {
+		return a.Pack("", funcArgs...)
+	}
+	input, err := m.Inputs.Pack(funcArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return append(m.ID, input...), nil
+}
+
+// FindMethod returns the ABI function by either its name, its canonical signature, eg.
+// `safeTransferFrom(address,address,uint256,bytes)`, or its 4-byte selector, eg. `0xb88d4fde`.
+// Overloaded functions, selected by name, are disambiguated by the number of arguments.
+func FindMethod(a abi.ABI, name string, nargs int) (abi.Method, error) {
+	if strings.HasPrefix(name, "0x") && len(name) == 10 {
+		id, err := hex.DecodeString(name[2:])
+		if err != nil {
+			return abi.Method{}, fmt.Errorf("Invalid function selector '%s'", name)
+		}
+		m, err := a.MethodById(id)
+		if err != nil {
+			return abi.Method{}, fmt.Errorf("Function selector '%s' not found in ABI", name)
+		}
+		return *m, nil
+	} else if strings.Contains(name, "(") {
+		sig := canonicalSig(name)
+		for _, m := range a.Methods {
+			if m.Sig == sig {
+				return m, nil
+			}
+		}
+		return abi.Method{}, fmt.Errorf("Function '%s' not found in ABI", sig)
+	}
+
+	// go-ethereum renames overloads `name0`, `name1`.., so match the original name, then the renamed
+	var named, matched []abi.Method
+	for _, m := range a.Methods {
+		if m.RawName == name {
+			named = append(named, m)
+			if len(m.Inputs) == nargs {
+				matched = append(matched, m)
+			}
+		}
+	}
+	if len(named) == 0 {
+		if m, ok := a.Methods[name]; ok {
+			return m, nil
+		}
+		return abi.Method{}, fmt.Errorf("Function '%s' not found in ABI", name)
+	} else if len(named) == 1 {
+		return named[0], nil
+	} else if len(matched) == 1 {
+		return matched[0], nil
+	}
+	candidates := make([]string, len(named))
+	for i, m := range named {
+		candidates[i] = m.Sig
+	}
+	sort.Strings(candidates)
+	return abi.Method{}, fmt.Errorf("Function '%s' is ambiguous with %d arguments, specify the signature, one of: %s",
+		name, nargs, strings.Join(candidates, ", "))
+}
+
+// canonicalSig returns the signature without whitespace, and with `uint`/`int` as `uint256`/`int256`
+func canonicalSig(sig string) string {
+	sig = strings.Join(strings.Fields(sig), "")
+	return intAliasRe.ReplaceAllString(sig, "${1}256${2}")
+}
+
+// DeployData returns the contract bytecode followed by the constructor, eg. `constructor(string,uint256)`, args
+func DeployData(bin []byte, constructor string, args ...string) ([]byte, error) {
+	input, err := parser.ParseConstructor(constructor, args)
+	if err != nil {
+		return nil, err
+	}
+	return append(bin, input...), nil
+}
+
+// ABIDeployData returns the contract bytecode followed by the ABI's constructor args
+func ABIDeployData(a abi.ABI, bin []byte, args ...string) ([]byte, error) {
+	if len(args) != len(a.Constructor.Inputs) {
+		return nil, fmt.Errorf("%s expects %d arguments, got %d", ConstructorSig(a), len(a.Constructor.Inputs), len(args))
+	}
+	input, err := ABIMethodData(a, "", args...)
+	if err != nil {
+		return nil, err
+	}
+	return append(bin, input...), nil
+}
+
+// ConstructorSig returns the ABI's constructor signature, eg. `constructor(string,uint256)`
+func ConstructorSig(a abi.ABI) string {
+	types := make([]string, len(a.Constructor.Inputs))
+	for i, input := range a.Constructor.Inputs {
+		types[i] = input.Type.String()
+	}
+	return "constructor(" + strings.Join(types, ",") + ")"
+}
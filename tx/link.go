@@ -0,0 +1,173 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// placeholderLen is the length of both the legacy `__Lib.sol:Lib_____`, and the hashed `__$<34 hex>$__`,
+// library placeholders
+const placeholderLen = 40
+
+// hashCommentRe matches the solc `// $<34 hex>$ -> path.sol:Lib` lines naming the hashed placeholders
+var hashCommentRe = regexp.MustCompile(`^//\s*\$([0-9a-fA-F]{34})\$\s*->\s*(\S+)`)
+
+// ReadLinkedBin reads the hex-encoded, compiled, contract bytecode file, linking the libraries
+func ReadLinkedBin(binFile string, libs map[string]common.Address) ([]byte, error) {
+	b, err := ioutil.ReadFile(binFile)
+	if err != nil {
+		return nil, err
+	}
+	return LinkBin(string(b), libs)
+}
+
+// LinkBin replaces the library placeholders of the, optionally 0x-prefixed, hex-encoded bytecode with the library addresses, returning
+// the decoded bytecode. Libraries are given by name, eg. `Math`, fully qualified name, eg. `contracts/Math.sol:Math`,
+// or for hashed placeholders, the 34 hex character placeholder hash. Unresolved placeholders are an error.
+func LinkBin(bin string, libs map[string]common.Address) ([]byte, error) {
+	// Keep the solc comments naming hashed placeholders, and drop them from the bytecode
+	names := make(map[string]string)
+	var code strings.Builder
+	for _, l := range strings.Split(bin, "\n") {
+		l = strings.TrimSpace(l)
+		if m := hashCommentRe.FindStringSubmatch(l); m != nil {
+			names[strings.ToLower(m[1])] = m[2]
+		} else if !strings.HasPrefix(l, "//") {
+			code.WriteString(l)
+		}
+	}
+	return link(code.String(), names, libs)
+}
+
+// link replaces the placeholders of the hex-encoded bytecode, with the names of the hashed placeholders
+func link(code string, names map[string]string, libs map[string]common.Address) ([]byte, error) {
+	code = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(code), "0x"), "0X")
+
+	// Placeholders start with `__`, which is never valid hex
+	var parts []string
+	for c := code; c != ""; {
+		i := strings.Index(c, "__")
+		if i < 0 {
+			parts = append(parts, c)
+			break
+		} else if len(c) < i+placeholderLen {
+			return nil, fmt.Errorf("Invalid library placeholder '%s'", c[i:])
+		}
+		parts = append(parts, c[:i], c[i:i+placeholderLen])
+		c = c[i+placeholderLen:]
+	}
+
+	// Every library of the bytecode, so libraries are only linked by name when the name is unique
+	linking := make(map[string]bool)
+	for _, p := range parts {
+		if strings.HasPrefix(p, "__") {
+			linking[placeholderName(p, names)] = true
+		}
+	}
+
+	var linked strings.Builder
+	var unresolved []string
+	seen := make(map[string]bool)
+	for _, p := range parts {
+		if !strings.HasPrefix(p, "__") {
+			linked.WriteString(p)
+			continue
+		}
+		addr, ok, err := resolveLibrary(p, names, linking, libs)
+		if err != nil {
+			return nil, err
+		} else if ok {
+			linked.WriteString(hex.EncodeToString(addr.Bytes()))
+			continue
+		}
+		name := placeholderName(p, names)
+		if !seen[name] {
+			seen[name] = true
+			unresolved = append(unresolved, name)
+		}
+	}
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("Unresolved libraries: %s", strings.Join(unresolved, ", "))
+	}
+	return hex.DecodeString(linked.String())
+}
+
+// placeholderName returns the library name of the placeholder, or for unnamed hashed placeholders, `$<hash>$`
+func placeholderName(p string, names map[string]string) string {
+	if p[2] != '$' {
+		return strings.TrimRight(p[2:placeholderLen-2], "_")
+	}
+	h := strings.ToLower(p[3:37])
+	if name, ok := names[h]; ok {
+		return name
+	}
+	return "$" + h + "$"
+}
+
+// resolveLibrary returns the address of the library matching the placeholder, by its fully qualified name or
+// hash, falling back to the library name when no other linked library has the same name
+func resolveLibrary(p string, names map[string]string, linking map[string]bool, libs map[string]common.Address) (common.Address, bool, error) {
+	name := placeholderName(p, names)
+	if addr, ok := libs[name]; ok {
+		return addr, true, nil
+	}
+
+	// Check the libraries in order, so the same libraries always link the same bytecode
+	keys := make([]string, 0, len(libs))
+	for k := range libs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var matches []string
+	for _, k := range keys {
+		if p[2] == '$' {
+			// Hashed placeholders are the first 17 bytes of the fully qualified name's hash
+			h := strings.ToLower(p[3:37])
+			if strings.ToLower(strings.Trim(k, "$")) == h || placeholderHash(k) == h {
+				return libs[k], true, nil
+			}
+		} else if len(k) > 36 && k[:36] == name {
+			// Legacy placeholders truncate the fully qualified name to 36 characters
+			matches = append(matches, k)
+		}
+	}
+	if len(matches) > 1 {
+		return common.Address{}, false, fmt.Errorf("Library '%s' is ambiguous, expected one of: %s", name, strings.Join(matches, ", "))
+	} else if len(matches) == 1 {
+		return libs[matches[0]], true, nil
+	}
+
+	lib := libraryName(name)
+	addr, ok := libs[lib]
+	if !ok {
+		return common.Address{}, false, nil
+	}
+	var candidates []string
+	for n := range linking {
+		if libraryName(n) == lib {
+			candidates = append(candidates, n)
+		}
+	}
+	if len(candidates) > 1 {
+		sort.Strings(candidates)
+		return common.Address{}, false, fmt.Errorf("Library '%s' is ambiguous, link by fully qualified name, one of: %s", lib, strings.Join(candidates, ", "))
+	}
+	return addr, true, nil
+}
+
+// libraryName returns the library name of the, optionally, fully qualified name, eg. `Math` of `contracts/Math.sol:Math`
+func libraryName(name string) string {
+	return name[strings.LastIndex(name, ":")+1:]
+}
+
+// placeholderHash returns the hash, of the hashed placeholder, for the fully qualified library name
+func placeholderHash(name string) string {
+	return hex.EncodeToString(crypto.Keccak256([]byte(name))[:17])
+}
@@ -0,0 +1,155 @@
+package tx
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	lib1 = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	lib2 = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+// legacyPlaceholder returns the `__path.sol:Lib___` placeholder of the fully qualified name
+func legacyPlaceholder(name string) string {
+	if len(name) > 36 {
+		name = name[:36]
+	}
+	return "__" + name + strings.Repeat("_", 38-len(name))
+}
+
+// hashedPlaceholder returns the `__$<hash>$__` placeholder of the fully qualified name
+func hashedPlaceholder(name string) string {
+	return "__$" + placeholderHash(name) + "$__"
+}
+
+func TestLinkBin(t *testing.T) {
+	tests := []struct {
+		name string
+		bin  string
+		libs map[string]common.Address
+		want string
+		err  string
+	}{
+		{
+			name: "no placeholders",
+			bin:  "0x6080",
+			want: "6080",
+		},
+		{
+			name: "legacy by name",
+			bin:  "60" + legacyPlaceholder("contracts/Math.sol:Math") + "80",
+			libs: map[string]common.Address{"Math": lib1},
+			want: "60" + hex.EncodeToString(lib1.Bytes()) + "80",
+		},
+		{
+			name: "legacy by fully qualified name",
+			bin:  "60" + legacyPlaceholder("contracts/Math.sol:Math") + "80",
+			libs: map[string]common.Address{"contracts/Math.sol:Math": lib1},
+			want: "60" + hex.EncodeToString(lib1.Bytes()) + "80",
+		},
+		{
+			name: "legacy truncated name",
+			bin:  legacyPlaceholder("contracts/libraries/VeryLongName.sol:Math"),
+			libs: map[string]common.Address{"contracts/libraries/VeryLongName.sol:Math": lib1},
+			want: hex.EncodeToString(lib1.Bytes()),
+		},
+		{
+			name: "hashed by name",
+			bin:  "60" + hashedPlaceholder("contracts/Math.sol:Math") + "\n// $" + placeholderHash("contracts/Math.sol:Math") + "$ -> contracts/Math.sol:Math",
+			libs: map[string]common.Address{"Math": lib1},
+			want: "60" + hex.EncodeToString(lib1.Bytes()),
+		},
+		{
+			name: "hashed by fully qualified name, without the comment",
+			bin:  hashedPlaceholder("contracts/Math.sol:Math"),
+			libs: map[string]common.Address{"contracts/Math.sol:Math": lib1},
+			want: hex.EncodeToString(lib1.Bytes()),
+		},
+		{
+			name: "hashed by hash",
+			bin:  hashedPlaceholder("contracts/Math.sol:Math"),
+			libs: map[string]common.Address{"$6ad30996409d058139477db06ae39abaac$": lib1},
+			want: hex.EncodeToString(lib1.Bytes()),
+		},
+		{
+			name: "hashed prefers the fully qualified name",
+			bin:  hashedPlaceholder("a.sol:Math") + "\n// $" + placeholderHash("a.sol:Math") + "$ -> a.sol:Math",
+			libs: map[string]common.Address{"Math": lib1, "a.sol:Math": lib2},
+			want: hex.EncodeToString(lib2.Bytes()),
+		},
+		{
+			name: "hashed prefers the hash, without the comment",
+			bin:  hashedPlaceholder("a.sol:Math"),
+			libs: map[string]common.Address{"Math": lib1, "a.sol:Math": lib2},
+			want: hex.EncodeToString(lib2.Bytes()),
+		},
+		{
+			name: "ambiguous name",
+			bin:  legacyPlaceholder("a.sol:Math") + legacyPlaceholder("b.sol:Math"),
+			libs: map[string]common.Address{"Math": lib1},
+			err:  "Library 'Math' is ambiguous, link by fully qualified name, one of: a.sol:Math, b.sol:Math",
+		},
+		{
+			name: "ambiguous name, with one fully qualified",
+			bin:  legacyPlaceholder("a.sol:Math") + legacyPlaceholder("b.sol:Math"),
+			libs: map[string]common.Address{"Math": lib1, "a.sol:Math": lib2},
+			err:  "Library 'Math' is ambiguous, link by fully qualified name, one of: a.sol:Math, b.sol:Math",
+		},
+		{
+			name: "same names, fully qualified",
+			bin:  legacyPlaceholder("a.sol:Math") + legacyPlaceholder("b.sol:Math"),
+			libs: map[string]common.Address{"Math": lib1, "a.sol:Math": lib1, "b.sol:Math": lib2},
+			want: hex.EncodeToString(lib1.Bytes()) + hex.EncodeToString(lib2.Bytes()),
+		},
+		{
+			name: "unresolved",
+			bin:  legacyPlaceholder("a.sol:Math") + hashedPlaceholder("b.sol:Strings"),
+			libs: map[string]common.Address{"Strings": lib1},
+			err:  "Unresolved libraries: a.sol:Math, $" + placeholderHash("b.sol:Strings") + "$",
+		},
+		{
+			name: "truncated placeholder",
+			bin:  "60__Math",
+			err:  "Invalid library placeholder '__Math'",
+		},
+	}
+	for _, tt := range tests {
+		// Map iteration is random, so repeat to check the linking doesn't depend on it
+		for i := 0; i < 20; i++ {
+			b, err := LinkBin(tt.bin, tt.libs)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("%s: expected error '%s', got '%v'", tt.name, tt.err, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			} else if got := hex.EncodeToString(b); got != tt.want {
+				t.Fatalf("%s: expected %s, got %s", tt.name, tt.want, got)
+			}
+		}
+	}
+}
+
+func TestArtifactLink(t *testing.T) {
+	a := &Artifact{
+		Bin:       "0x60" + hashedPlaceholder("a.sol:Math") + hashedPlaceholder("b.sol:Math"),
+		Libraries: []string{"a.sol:Math", "b.sol:Math"},
+	}
+	if _, err := a.Link(map[string]common.Address{"Math": lib1}); err == nil {
+		t.Fatal("expected linking by an ambiguous library name to fail")
+	}
+	b, err := a.Link(map[string]common.Address{"a.sol:Math": lib1, "b.sol:Math": lib2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "60" + hex.EncodeToString(lib1.Bytes()) + hex.EncodeToString(lib2.Bytes())
+	if got := hex.EncodeToString(b); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}